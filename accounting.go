@@ -0,0 +1,83 @@
+package keeper
+
+import (
+	"runtime"
+	"time"
+)
+
+// BuildStats is the rough cost of building a bean: injecting it and running
+// its initializer, or running its factory. Allocations are process-wide, so
+// they include whatever ran concurrently, and the beans registered by an
+// initializer count for it too.
+type BuildStats struct {
+	Bean       string        `json:"bean"`
+	Duration   time.Duration `json:"duration"`
+	Allocs     uint64        `json:"allocs"`     // heap objects allocated
+	AllocBytes uint64        `json:"allocBytes"` // heap bytes allocated
+}
+
+// AccountResources is an Option that measures the BuildStats of every bean,
+// reported by Stats and Beans. It reads the memory statistics of the runtime
+// twice per build, which stops the world briefly.
+func AccountResources() Option {
+	return optionFunc(func(c *Container) {
+		c.accounting = true
+	})
+}
+
+// OnBuild is an Option that measures the BuildStats of every bean like
+// AccountResources, and invokes hook after each build.
+func OnBuild(hook func(BuildStats)) Option {
+	return optionFunc(func(c *Container) {
+		c.accounting = true
+		c.buildHooks = append(c.buildHooks, hook)
+	})
+}
+
+// Stats returns the BuildStats of the last build of the beans in the Ordering
+// of the container, the beans which weren't built being left out. It's empty unless
+// AccountResources or OnBuild is set.
+func (c *Container) Stats() []BuildStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	stats := make([]BuildStats, 0, len(c.stats))
+	for _, name := range c.enumeration() {
+		if s, ok := c.stats[name]; ok {
+			stats = append(stats, s)
+		}
+	}
+	return stats
+}
+
+// measure runs build, recording its BuildStats for the bean of name when
+// it succeeds.
+func (c *Container) measure(name string, build func() error) error {
+	if !c.accounting {
+		return build()
+	}
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	begin := time.Now()
+	if err := build(); err != nil {
+		return err
+	}
+	elapsed := time.Since(begin)
+	runtime.ReadMemStats(&after)
+	s := BuildStats{
+		Bean:       name,
+		Duration:   elapsed,
+		Allocs:     after.Mallocs - before.Mallocs,
+		AllocBytes: after.TotalAlloc - before.TotalAlloc,
+	}
+	c.mu.Lock()
+	if c.stats == nil {
+		c.stats = make(map[string]BuildStats)
+	}
+	c.stats[name] = s
+	hooks := c.buildHooks
+	c.mu.Unlock()
+	for _, hook := range hooks {
+		hook(s)
+	}
+	return nil
+}
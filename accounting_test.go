@@ -0,0 +1,43 @@
+package keeper
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type allocatingSrv struct {
+	buf []byte
+}
+
+func (s *allocatingSrv) AfterPropertySet() {
+	s.buf = make([]byte, 1<<20)
+}
+
+func TestContainer_AccountResources(t *testing.T) {
+	var built []string
+	c := New(OnBuild(func(s BuildStats) { built = append(built, s.Bean) }))
+	c.Register(new(allocatingSrv), Name("allocating"))
+	c.RegisterFactory(func(Keeper) (interface{}, error) { return &HelloSrv{}, nil }, Name("lazy"))
+	if stats := c.Stats(); len(stats) != 1 || stats[0].AllocBytes < 1<<20 || stats[0].Allocs == 0 {
+		t.Fatalf("got %+v", stats)
+	}
+	c.Find("lazy")
+	if len(built) != 2 || built[1] != "lazy" {
+		t.Fatalf("got builds %v", built)
+	}
+	info := c.Beans()[0]
+	if info.Build == nil || info.Build.Bean != "allocating" {
+		t.Fatalf("got %+v", info)
+	}
+	if _, err := json.Marshal(info); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestContainer_StatsDisabled(t *testing.T) {
+	c := New()
+	c.Register(new(allocatingSrv), Name("allocating"))
+	if stats := c.Stats(); len(stats) != 0 {
+		t.Fatalf("got %+v without accounting", stats)
+	}
+}
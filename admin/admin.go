@@ -0,0 +1,207 @@
+// Package admin serves an admin API to inspect and reconfigure the beans of
+// a running container, so operators could, for instance, swap a client for
+// its fallback without a deploy:
+//
+//	h := admin.NewHandler(c,
+//		admin.Authorize(func(r *http.Request, action admin.Action) error { ... }),
+//		admin.Replacement("stdoutSink", newStdoutSink))
+//	go admin.ServeUnix(ctx, "/run/app/admin.sock", h)
+//
+// Its routes are
+//
+//	GET  /beans?label=tier=db    beans with all the labels, as JSON
+//	GET  /graph                  dependency graph, as DOT
+//	GET  /state                  state of the beans, as keeper.DumpState writes it
+//	POST /swap?name=a&with=b     swap bean a with the registered bean b
+//	POST /replace?name=a&with=r  swap bean a with a bean built by the replacement r
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/tooky0630/keeper"
+)
+
+// Action is what a request to the admin API does.
+type Action string
+
+const (
+	ActionRead    Action = "read"
+	ActionSwap    Action = "swap"
+	ActionReplace Action = "replace"
+)
+
+// Authorizer decides whether r may perform action, a non nil error refuses it.
+type Authorizer func(r *http.Request, action Action) error
+
+// Option configures the admin Handler.
+type Option func(*Handler)
+
+// Authorize is an Option that checks every request with authorize. Without
+// it, only ActionRead is allowed.
+func Authorize(authorize Authorizer) Option {
+	return func(h *Handler) {
+		h.authorize = authorize
+	}
+}
+
+// Replacement is an Option that makes factory available to /replace under name.
+func Replacement(name string, factory keeper.FactoryFunc) Option {
+	return func(h *Handler) {
+		h.replacements[name] = factory
+	}
+}
+
+// Handler serves the admin API of a container.
+type Handler struct {
+	keeper       keeper.Keeper
+	authorize    Authorizer
+	replacements map[string]keeper.FactoryFunc
+	mux          *http.ServeMux
+}
+
+// NewHandler returns the admin Handler of k.
+func NewHandler(k keeper.Keeper, opts ...Option) *Handler {
+	h := &Handler{keeper: k, replacements: make(map[string]keeper.FactoryFunc), mux: http.NewServeMux()}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.mux.Handle("/beans", h.route(http.MethodGet, ActionRead, h.beans))
+	h.mux.Handle("/graph", h.route(http.MethodGet, ActionRead, h.graph))
+	h.mux.Handle("/state", h.route(http.MethodGet, ActionRead, h.state))
+	h.mux.Handle("/swap", h.route(http.MethodPost, ActionSwap, h.swap))
+	h.mux.Handle("/replace", h.route(http.MethodPost, ActionReplace, h.replace))
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// route checks the method and authorization of the requests to fn.
+func (h *Handler) route(method string, action Action, fn func(http.ResponseWriter, *http.Request) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if err := h.authorized(r, action); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		if err := fn(w, r); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	})
+}
+
+func (h *Handler) authorized(r *http.Request, action Action) error {
+	if h.authorize != nil {
+		return h.authorize(r, action)
+	}
+	if action != ActionRead {
+		return fmt.Errorf("%s needs an authorizer", action)
+	}
+	return nil
+}
+
+func (h *Handler) beans(w http.ResponseWriter, r *http.Request) error {
+	labels := make(map[string]string)
+	for _, label := range r.URL.Query()["label"] {
+		kv := strings.SplitN(label, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("invalid label %q, want key=value", label)
+		}
+		labels[kv[0]] = kv[1]
+	}
+	infos := []keeper.BeanInfo{}
+	for _, info := range h.keeper.Beans() {
+		if matchLabels(info.Labels, labels) {
+			infos = append(infos, info)
+		}
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	return json.NewEncoder(w).Encode(infos)
+}
+
+func matchLabels(labels, want map[string]string) bool {
+	for k, v := range want {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (h *Handler) graph(w http.ResponseWriter, r *http.Request) error {
+	w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+	return keeper.WriteDOT(w, h.keeper)
+}
+
+func (h *Handler) state(w http.ResponseWriter, r *http.Request) error {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	return h.keeper.DumpState(w)
+}
+
+func (h *Handler) swap(w http.ResponseWriter, r *http.Request) error {
+	name, with := r.URL.Query().Get("name"), r.URL.Query().Get("with")
+	bean := h.keeper.Find(with)
+	if bean == nil {
+		return fmt.Errorf("no bean %q to swap %s with", with, name)
+	}
+	if _, err := h.keeper.Swap(name, bean); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "swapped %s with %s\n", name, with)
+	return nil
+}
+
+func (h *Handler) replace(w http.ResponseWriter, r *http.Request) error {
+	name, with := r.URL.Query().Get("name"), r.URL.Query().Get("with")
+	factory, ok := h.replacements[with]
+	if !ok {
+		return fmt.Errorf("no replacement %q", with)
+	}
+	bean, err := factory(h.keeper)
+	if err != nil {
+		return fmt.Errorf("failed to build replacement %s: %v", with, err)
+	}
+	if _, err := h.keeper.Swap(name, bean); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "replaced %s with %s\n", name, with)
+	return nil
+}
+
+// ServeUnix serves h on a unix socket at path, only accessible to the user
+// of the process, until ctx is done. A stale socket at path is removed.
+func ServeUnix(ctx context.Context, path string, h http.Handler) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		l.Close()
+		return err
+	}
+	srv := &http.Server{Handler: h}
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+	if err := srv.Serve(l); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tooky0630/keeper"
+)
+
+type client struct {
+	addr string
+}
+
+type service struct {
+	Client *client `name:"client"`
+}
+
+func newAdmin(opts ...Option) (keeper.Keeper, *service, *Handler) {
+	c := keeper.New()
+	c.Register(&client{addr: "primary"}, keeper.Name("client"), keeper.Label("tier", "db"))
+	c.Register(&client{addr: "fallback"}, keeper.Name("fallbackClient"))
+	svc := new(service)
+	c.Register(svc, keeper.Name("service"))
+	return c, svc, NewHandler(c, opts...)
+}
+
+func serve(h http.Handler, method, target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+	return rec
+}
+
+func TestHandler_Beans(t *testing.T) {
+	_, _, h := newAdmin()
+	rec := serve(h, http.MethodGet, "/beans?label=tier=db")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"name":"client"`) ||
+		strings.Contains(rec.Body.String(), "fallbackClient") {
+		t.Fatalf("got %d %s", rec.Code, rec.Body)
+	}
+	if rec := serve(h, http.MethodGet, "/graph"); !strings.Contains(rec.Body.String(), `"service" -> "client"`) {
+		t.Fatalf("got %s", rec.Body)
+	}
+}
+
+func TestHandler_Swap(t *testing.T) {
+	_, svc, h := newAdmin()
+	if rec := serve(h, http.MethodPost, "/swap?name=client&with=fallbackClient"); rec.Code != http.StatusForbidden {
+		t.Fatalf("got %d, swaps need an authorizer", rec.Code)
+	}
+
+	_, svc, h = newAdmin(Authorize(func(r *http.Request, action Action) error {
+		if r.Header.Get("X-Operator") == "" {
+			return errors.New("unknown operator")
+		}
+		return nil
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/swap?name=client&with=fallbackClient", nil)
+	req.Header.Set("X-Operator", "alice")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || svc.Client.addr != "fallback" {
+		t.Fatalf("got %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestHandler_Replace(t *testing.T) {
+	allowAll := Authorize(func(*http.Request, Action) error { return nil })
+	replacement := Replacement("local", func(keeper.Keeper) (interface{}, error) {
+		return &client{addr: "local"}, nil
+	})
+	_, svc, h := newAdmin(allowAll, replacement)
+	if rec := serve(h, http.MethodPost, "/replace?name=client&with=local"); rec.Code != http.StatusOK {
+		t.Fatalf("got %d %s", rec.Code, rec.Body)
+	}
+	if svc.Client.addr != "local" {
+		t.Fatalf("got %s", svc.Client.addr)
+	}
+	if rec := serve(h, http.MethodPost, "/replace?name=client&with=unknown"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("got %d", rec.Code)
+	}
+	if rec := serve(h, http.MethodGet, "/replace?name=client&with=local"); rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("got %d", rec.Code)
+	}
+}
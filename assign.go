@@ -0,0 +1,143 @@
+package keeper
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	_lockerType  = reflect.TypeOf((*sync.Locker)(nil)).Elem()
+	_noCopyTypes = map[reflect.Type]bool{
+		reflect.TypeOf((*sync.WaitGroup)(nil)).Elem(): true,
+		reflect.TypeOf((*sync.Once)(nil)).Elem():      true,
+		reflect.TypeOf((*sync.Cond)(nil)).Elem():      true,
+		reflect.TypeOf((*sync.Map)(nil)).Elem():       true,
+		reflect.TypeOf((*sync.Pool)(nil)).Elem():      true,
+	}
+)
+
+// assignValue returns the value of bean which could be set into a field of type typ.
+//
+// The bean is used as is when it is assignable to the field, a channel bean may be
+// narrowed to a receive-only or send-only field, and a pointer bean is dereferenced
+// when the field holds its element type. Synchronization primitives are never
+// dereferenced, since a copied WaitGroup or Mutex is a different one.
+//
+// A field pointing to an interface the bean implements gets a pointer to a new
+// interface value holding the bean, which saves declaring an extra variable.
+//
+// With convert, a bean of a different numeric type or of a type with the same
+// underlying type is converted to the field type as well, e.g. int to int64.
+func assignValue(typ reflect.Type, bean interface{}, convert bool) (reflect.Value, error) {
+	bv := reflect.ValueOf(bean)
+	bt := bv.Type()
+	if bt.AssignableTo(typ) {
+		return bv, nil
+	}
+	if typ.Kind() == reflect.Ptr && typ.Elem().Kind() == reflect.Interface && bt.Implements(typ.Elem()) { // boxed into *I
+		box := reflect.New(typ.Elem())
+		box.Elem().Set(bv)
+		return box, nil
+	}
+	if typ.Kind() == reflect.Interface && bt.Kind() != reflect.Ptr && reflect.PtrTo(bt).Implements(typ) {
+		return reflect.Value{}, fmt.Errorf("cannot use %v as %v, its methods have pointer receivers: register a %v", bt, typ, reflect.PtrTo(bt))
+	}
+	if bt.Kind() == reflect.Ptr && bt.Elem().Kind() == reflect.Chan { // registered as *chan T
+		bv, bt = bv.Elem(), bt.Elem()
+		if bt.AssignableTo(typ) {
+			return bv, nil
+		}
+	}
+	if bt.Kind() == reflect.Chan {
+		if typ.Kind() == reflect.Chan && bt.ChanDir() == reflect.BothDir && bt.Elem() == typ.Elem() {
+			return bv.Convert(typ), nil
+		}
+		return reflect.Value{}, fmt.Errorf("cannot use %v as %v", bt, typ)
+	}
+	if bt.Kind() == reflect.Ptr && bt.Elem().AssignableTo(typ) {
+		if isNoCopy(bt.Elem()) {
+			return reflect.Value{}, fmt.Errorf("%v must not be copied, declare the field as %v", bt.Elem(), bt)
+		}
+		return bv.Elem(), nil
+	}
+	if bt.Kind() == reflect.Ptr && !isNoCopy(bt.Elem()) && convertible(bt.Elem(), typ) {
+		bv, bt = bv.Elem(), bt.Elem()
+	}
+	if convertible(bt, typ) {
+		if !convert {
+			return reflect.Value{}, fmt.Errorf("cannot use %v as %v without the AllowConversion option", bt, typ)
+		}
+		return bv.Convert(typ), nil
+	}
+	return reflect.Value{}, fmt.Errorf("cannot use %v as %v", bt, typ)
+}
+
+// convertible reports whether a value of from could be explicitly converted
+// to a field of type to without losing its meaning: between numeric types, or
+// between types sharing the underlying type. Conversions like int to string
+// are excluded.
+func convertible(from, to reflect.Type) bool {
+	if isNumeric(from) && isNumeric(to) {
+		return true
+	}
+	return from.Kind() == to.Kind() && !isNumeric(from) && from.ConvertibleTo(to)
+}
+
+func isNumeric(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// isNoCopy reports whether values of typ must not be copied after first use.
+func isNoCopy(typ reflect.Type) bool {
+	return _noCopyTypes[typ] || reflect.PtrTo(typ).Implements(_lockerType)
+}
+
+// AllowConversion is an Option that converts beans into fields of a different
+// but compatible type, like an int bean into an int64 field, or a named type
+// into its underlying type. Without it such wiring is rejected.
+func AllowConversion() Option {
+	return optionFunc(func(c *Container) {
+		c.allowConversion = true
+	})
+}
+
+// StrictTypes is an Option that only injects beans of the very type of the
+// field, or implementing its interface: pointer beans aren't dereferenced
+// into struct fields and nothing is converted, even with AllowConversion.
+// Fields pointing to an interface and channel fields of a narrower
+// direction still get the bean, which keeps its identity.
+func StrictTypes() Option {
+	return optionFunc(func(c *Container) {
+		c.strictTypes = true
+	})
+}
+
+// assign is assignValue under the rules of the container.
+func (c *Container) assign(typ reflect.Type, bean interface{}) (reflect.Value, error) {
+	if c.strictTypes && !identical(typ, reflect.TypeOf(bean)) {
+		return reflect.Value{}, fmt.Errorf("cannot use %v as %v with StrictTypes", reflect.TypeOf(bean), typ)
+	}
+	return assignValue(typ, bean, c.allowConversion)
+}
+
+// identical reports whether a bean of type bt keeps its identity in a field of type typ.
+func identical(typ, bt reflect.Type) bool {
+	switch {
+	case bt == typ:
+		return true
+	case typ.Kind() == reflect.Interface:
+		return bt.Implements(typ)
+	case typ.Kind() == reflect.Ptr && typ.Elem().Kind() == reflect.Interface:
+		return bt.Implements(typ.Elem())
+	case typ.Kind() == reflect.Chan && bt.Kind() == reflect.Chan:
+		return bt.ChanDir() == reflect.BothDir && bt.Elem() == typ.Elem()
+	}
+	return false
+}
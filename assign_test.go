@@ -0,0 +1,171 @@
+package keeper
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+type event struct{ id int }
+
+type eventBus chan event
+
+type eventConsumer struct {
+	events <-chan event    `name:"events"`
+	bus    chan<- event    `name:"bus"`
+	wg     *sync.WaitGroup `name:"wg"`
+}
+
+func TestContainer_RegisterChannel(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	events := make(chan event, 1)
+	if err := c.Register(events, Name("events")); err != nil {
+		t.Fatal(err)
+	}
+	bus := make(eventBus, 1)
+	if err := c.Register(bus, Name("bus")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Register(new(sync.WaitGroup), Name("wg")); err != nil {
+		t.Fatal(err)
+	}
+	consumer := new(eventConsumer)
+	if err := c.Register(consumer, Name("consumer")); err != nil {
+		t.Fatal(err)
+	}
+	events <- event{id: 1}
+	if e := <-consumer.events; e.id != 1 {
+		t.Fatalf("got event %d, want 1", e.id)
+	}
+	consumer.bus <- event{id: 2}
+	if e := <-bus; e.id != 2 {
+		t.Fatalf("got event %d, want 2", e.id)
+	}
+	if consumer.wg != c.Find("wg") {
+		t.Fatal("wait group should be injected by pointer")
+	}
+}
+
+type waitGroupCopier struct {
+	wg sync.WaitGroup `name:"wg"`
+}
+
+func TestContainer_RegisterRejectsLockCopy(t *testing.T) {
+	c := New()
+	if err := c.Register(new(sync.WaitGroup), Name("wg")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Register(new(waitGroupCopier), Name("copier")); err == nil {
+		t.Fatal("expected error when copying a wait group")
+	}
+}
+
+type Greeter interface {
+	Hello() string
+}
+
+type Port int
+
+type typedConsumer struct {
+	greeter Greeter  `name:"helloService"`
+	port    int64    `name:"port"`
+	named   Port     `name:"namedPort"`
+	srv     HelloSrv `name:"helloService"`
+}
+
+func TestContainer_RegisterConversion(t *testing.T) {
+	unsafeOnly(t)
+	c := New(AllowConversion())
+	c.Register(&HelloSrv{word: "keeper"}, Name("helloService"))
+	c.Register(8080, Name("port"))
+	c.Register(Port(9090), Name("namedPort"))
+	consumer := new(typedConsumer)
+	if err := c.Register(consumer, Name("consumer")); err != nil {
+		t.Fatal(err)
+	}
+	if consumer.greeter == nil || consumer.port != 8080 || consumer.named != 9090 || consumer.srv.word != "keeper" {
+		t.Fatalf("got %+v", consumer)
+	}
+}
+
+type mistypedConsumer struct {
+	srv HelloCtl `name:"helloService"`
+}
+
+func TestContainer_RegisterRejectsMismatch(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	c.Register(new(HelloSrv), Name("helloService"))
+	c.Register(8080, Name("port"))
+	c.Register(Port(9090), Name("namedPort"))
+	if err := c.Register(new(mistypedConsumer), Name("mistyped")); err == nil {
+		t.Fatal("expected error for a different struct type")
+	}
+	err := c.Register(new(typedConsumer), Name("consumer"))
+	if err == nil || !strings.Contains(err.Error(), "AllowConversion") {
+		t.Fatalf("expected conversion to be refused, got %v", err)
+	}
+}
+
+type boxedConsumer struct {
+	greeter  Greeter  `name:"helloService"`
+	boxed    *Greeter `name:"helloService"`
+	optional *Greeter `name:"missing,optional"`
+}
+
+func TestContainer_RegisterBoxing(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	c.Register(&HelloSrv{word: "keeper"}, Name("helloService"))
+	consumer := new(boxedConsumer)
+	if err := c.Register(consumer, Name("consumer")); err != nil {
+		t.Fatal(err)
+	}
+	if consumer.greeter != c.Find("helloService") || consumer.boxed == nil || *consumer.boxed != consumer.greeter {
+		t.Fatalf("got %+v", consumer)
+	}
+	if consumer.optional != nil {
+		t.Fatal("missing optional field should be left nil")
+	}
+}
+
+func TestContainer_RegisterValueReceiverHint(t *testing.T) {
+	c := New()
+	c.Register(HelloSrv{}, Name("helloService"))
+	err := c.Register(new(boxedConsumer), Name("consumer"))
+	if err == nil || !strings.Contains(err.Error(), "register a *keeper.HelloSrv") {
+		t.Fatalf("expected a hint to register the pointer, got %v", err)
+	}
+}
+
+type celsius struct{ degrees float64 }
+
+type fahrenheit struct{ degrees float64 }
+
+type thermometer struct {
+	reading fahrenheit `name:"reading"`
+}
+
+func TestContainer_StrictTypes(t *testing.T) {
+	unsafeOnly(t)
+	c := New(AllowConversion())
+	c.Register(celsius{degrees: 20}, Name("reading"))
+	if err := c.Register(new(thermometer), Name("lenient")); err != nil {
+		t.Fatalf("same underlying structs are converted with AllowConversion, got %v", err)
+	}
+
+	c = New(AllowConversion(), StrictTypes())
+	c.Register(celsius{degrees: 20}, Name("reading"))
+	c.Register(&HelloSrv{}, Name("helloService"))
+	err := c.Register(new(thermometer), Name("strict"))
+	if err == nil || !strings.Contains(err.Error(), "StrictTypes") {
+		t.Fatalf("expected StrictTypes to refuse the conversion, got %v", err)
+	}
+	if err := c.Register(new(HelloCtl), Name("helloCtl")); err == nil {
+		t.Fatal("expected StrictTypes to refuse copying the pointer bean")
+	}
+	if err := c.Register(new(boxedConsumer), Name("boxed")); err != nil {
+		t.Fatalf("interfaces keep the identity of the bean, got %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package keeper
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// AuditLog lists the injections into the registered beans, one per line
+// like "helloCtl.helloSrv <- helloService (*keeper.HelloSrv)", sorted so the
+// log is stable across runs. Committed as a golden file, it makes changes of
+// the wiring visible in reviews. The type is the one of the injected value.
+func (c *Container) AuditLog() string {
+	c.mu.RLock()
+	injected := c.injected
+	c.mu.RUnlock()
+	lines := make([]string, 0, len(injected))
+	for _, f := range injected {
+		if f.bean == "" {
+			continue
+		}
+		field := reflect.TypeOf(f.ptr).Elem().Field(f.field)
+		fv := reflect.ValueOf(f.ptr).Elem().Field(f.field)
+		typ := fv.Type()
+		if fv.Kind() == reflect.Interface && !fv.IsNil() {
+			typ = fv.Elem().Type()
+		}
+		lines = append(lines, fmt.Sprintf("%s.%s <- %s (%v)", f.bean, field.Name, f.source, typ))
+	}
+	sort.Strings(lines)
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
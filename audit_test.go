@@ -0,0 +1,17 @@
+package keeper
+
+import "testing"
+
+func TestContainer_AuditLog(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	c.Register(&HelloSrv{}, Name("helloService"))
+	c.Register(&noopCache{}, Name("cache"))
+	c.Register(new(HelloCtl), Name("helloCtl"))
+	c.Register(new(cachedRepo), Name("repo"))
+	want := "helloCtl.helloSrv <- helloService (keeper.HelloSrv)\n" +
+		"repo.cache <- cache (*keeper.noopCache)\n"
+	if got := c.AuditLog(); got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,97 @@
+package keeper
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Registration is a bean and its options, as given to Register.
+type Registration struct {
+	Bean    interface{}
+	Options []RegisterOption
+}
+
+// Bean returns the Registration of bean with opts.
+func Bean(bean interface{}, opts ...RegisterOption) Registration {
+	return Registration{Bean: bean, Options: opts}
+}
+
+// BestEffort is an Option that makes RegisterBatch keep registering after a
+// bean fails or panics, so one broken optional subsystem doesn't abort the
+// whole build. Beans depending on a failed bean fail in turn.
+func BestEffort() Option {
+	return optionFunc(func(c *Container) {
+		c.bestEffort = true
+	})
+}
+
+// BeanResult is the outcome of one Registration of a batch.
+type BeanResult struct {
+	Name string
+	Err  error
+}
+
+// BatchError is returned by RegisterBatch in BestEffort mode when any bean failed.
+type BatchError struct {
+	Results []BeanResult // one per Registration, in order
+}
+
+func (e *BatchError) Error() string {
+	var failed []string
+	for _, r := range e.Results {
+		if r.Err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", r.Name, r.Err))
+		}
+	}
+	return fmt.Sprintf("%d of %d beans failed: %s", len(failed), len(e.Results), strings.Join(failed, "; "))
+}
+
+// Failed returns the results of the beans which failed.
+func (e *BatchError) Failed() []BeanResult {
+	var failed []BeanResult
+	for _, r := range e.Results {
+		if r.Err != nil {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// RegisterBatch registers regs in order. It stops at the first failure,
+// unless the container is BestEffort, which registers every bean it can and
+// returns a *BatchError holding the result of each.
+func (c *Container) RegisterBatch(regs ...Registration) error {
+	results := make([]BeanResult, len(regs))
+	var failed bool
+	for i, reg := range regs {
+		var options registerOptions
+		for _, o := range reg.Options {
+			o.applyRegisterOption(&options)
+		}
+		results[i].Name = options.Name
+		if !c.bestEffort {
+			if err := c.Register(reg.Bean, reg.Options...); err != nil {
+				return fmt.Errorf("register %s: %v", options.Name, err)
+			}
+			continue
+		}
+		if err := c.registerIsolated(reg); err != nil {
+			results[i].Err = err
+			failed = true
+		}
+	}
+	if failed {
+		return &BatchError{Results: results}
+	}
+	return nil
+}
+
+// registerIsolated registers reg, turning a panic into an error.
+func (c *Container) registerIsolated(reg Registration) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return c.Register(reg.Bean, reg.Options...)
+}
@@ -0,0 +1,56 @@
+package keeper
+
+import "testing"
+
+type panickingPlugin struct{}
+
+func (*panickingPlugin) AfterPropertySet() {
+	panic("plugin is broken")
+}
+
+type pluginConsumer struct {
+	plugin *panickingPlugin `name:"plugin"`
+}
+
+func TestContainer_RegisterBatch(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	err := c.RegisterBatch(
+		Bean(new(HelloSrv), Name("helloService")),
+		Bean(new(HelloCtl), Name("helloCtl")),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Find("helloCtl") == nil {
+		t.Fatal("helloCtl is not registered")
+	}
+	if err := c.RegisterBatch(Bean(new(pluginConsumer), Name("consumer")), Bean(new(HelloSrv), Name("other"))); err == nil {
+		t.Fatal("expected error")
+	}
+	if c.Find("other") != nil {
+		t.Fatal("batch should stop at the first failure")
+	}
+}
+
+func TestContainer_RegisterBatchBestEffort(t *testing.T) {
+	unsafeOnly(t)
+	c := New(BestEffort())
+	err := c.RegisterBatch(
+		Bean(new(panickingPlugin), Name("plugin")),
+		Bean(new(pluginConsumer), Name("consumer")),
+		Bean(new(HelloSrv), Name("helloService")),
+		Bean(new(HelloCtl), Name("helloCtl")),
+	)
+	batchErr, ok := err.(*BatchError)
+	if !ok {
+		t.Fatalf("got %v, want *BatchError", err)
+	}
+	failed := batchErr.Failed()
+	if len(failed) != 2 || failed[0].Name != "plugin" || failed[1].Name != "consumer" {
+		t.Fatalf("got %+v", batchErr.Results)
+	}
+	if c.Find("helloCtl") == nil {
+		t.Fatal("unrelated beans should be registered")
+	}
+}
@@ -0,0 +1,67 @@
+package keeper
+
+import (
+	"fmt"
+	"testing"
+)
+
+func BenchmarkContainer_Find(b *testing.B) {
+	c := New()
+	for i := 0; i < 64; i++ {
+		c.Register(new(HelloSrv), Name(fmt.Sprintf("helloService%d", i)))
+	}
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			c.Find("helloService42")
+		}
+	})
+}
+
+func BenchmarkContainer_All(b *testing.B) {
+	c := New()
+	for i := 0; i < 64; i++ {
+		c.Register(new(HelloSrv), Name(fmt.Sprintf("helloService%d", i)))
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for range c.All() {
+		}
+	}
+}
+
+func BenchmarkContainer_Range(b *testing.B) {
+	c := New()
+	for i := 0; i < 64; i++ {
+		c.Register(new(HelloSrv), Name(fmt.Sprintf("helloService%d", i)))
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c.Range(func(string, interface{}) bool { return true })
+	}
+}
+
+func BenchmarkContainer_Register(b *testing.B) {
+	names := make([]string, b.N)
+	for i := range names {
+		names[i] = fmt.Sprintf("helloCtl%d", i)
+	}
+	c := New()
+	c.Register(new(HelloSrv), Name("helloService"))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Register(new(HelloCtl), Name(names[i]))
+	}
+}
+
+func BenchmarkContainer_Provider(b *testing.B) {
+	c := New(WithoutCallSites())
+	c.Register(new(HelloSrv), Name("helloService"))
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			c.Provider(new(HelloCtl))
+		}
+	})
+}
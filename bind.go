@@ -0,0 +1,57 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+var _contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// BindFunc resolves the dependencies of fn from k by type, and returns fn
+// with them bound: a func taking the remaining parameters. The dependencies
+// are the leading parameters which are pointers or interfaces, except
+// context.Context, the parameters from the first other one on are left to
+// the caller. Dependencies are resolved once, by BindFunc.
+//
+//	bound, err := keeper.BindFunc(c, func(srv *HelloSrv, name string) string {
+//		return srv.Say(name)
+//	})
+//	greet := bound.(func(string) string)
+func BindFunc(k Keeper, fn interface{}) (interface{}, error) {
+	fv := reflect.ValueOf(fn)
+	if fv.Kind() != reflect.Func || fv.IsNil() {
+		return nil, fmt.Errorf("cannot bind %T, it isn't a func", fn)
+	}
+	ft := fv.Type()
+	var deps []reflect.Value
+	for i := 0; i < ft.NumIn(); i++ {
+		typ := ft.In(i)
+		if ft.IsVariadic() && i == ft.NumIn()-1 || typ == _contextType ||
+			typ.Kind() != reflect.Ptr && typ.Kind() != reflect.Interface {
+			break
+		}
+		bean, err := k.ResolveType(typ)
+		if err != nil {
+			return nil, fmt.Errorf("cannot bind parameter %d of %v: %w", i, ft, err)
+		}
+		deps = append(deps, reflect.ValueOf(bean))
+	}
+	in := make([]reflect.Type, 0, ft.NumIn()-len(deps))
+	for i := len(deps); i < ft.NumIn(); i++ {
+		in = append(in, ft.In(i))
+	}
+	out := make([]reflect.Type, ft.NumOut())
+	for i := range out {
+		out[i] = ft.Out(i)
+	}
+	bound := reflect.FuncOf(in, out, ft.IsVariadic())
+	return reflect.MakeFunc(bound, func(args []reflect.Value) []reflect.Value {
+		all := make([]reflect.Value, 0, len(deps)+len(args))
+		all = append(append(all, deps...), args...)
+		if ft.IsVariadic() {
+			return fv.CallSlice(all)
+		}
+		return fv.Call(all)
+	}).Interface(), nil
+}
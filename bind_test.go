@@ -0,0 +1,35 @@
+package keeper
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestBindFunc(t *testing.T) {
+	c := New()
+	c.Register(&HelloSrv{word: "hello"}, Name("helloService"))
+	bound, err := BindFunc(c, func(srv *HelloSrv, ctx context.Context, names ...string) string {
+		return srv.word + " " + strings.Join(names, ", ")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	greet, ok := bound.(func(context.Context, ...string) string)
+	if !ok {
+		t.Fatalf("got %T", bound)
+	}
+	if got := greet(context.Background(), "alice", "bob"); got != "hello alice, bob" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestBindFuncMissing(t *testing.T) {
+	_, err := BindFunc(New(), func(srv *HelloSrv) {})
+	if err == nil || !strings.Contains(err.Error(), "parameter 0") {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := BindFunc(New(), "greet"); err == nil {
+		t.Fatal("expected a non func to be refused")
+	}
+}
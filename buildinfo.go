@@ -0,0 +1,101 @@
+package keeper
+
+import (
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// BuildInfoBean is the name of the BuildInfo bean registered by WithBuildInfo.
+const BuildInfoBean = "buildInfo"
+
+// BuildInfo describes the build of the program, for handlers and logs to
+// report the version of the service:
+//
+//	type versionHandler struct {
+//		build *keeper.BuildInfo `name:"buildInfo"`
+//	}
+type BuildInfo struct {
+	Path      string    `json:"path,omitempty"` // of the main module
+	Version   string    `json:"version,omitempty"`
+	Revision  string    `json:"revision,omitempty"` // of the version control system
+	Time      time.Time `json:"time,omitempty"`     // of the revision
+	Modified  bool      `json:"modified,omitempty"` // built from a modified tree
+	GoVersion string    `json:"goVersion"`
+}
+
+// The linker may set these, which take precedence over debug.ReadBuildInfo:
+//
+//	go build -ldflags "-X github.com/tooky0630/keeper.buildVersion=v1.2.3"
+var (
+	buildVersion  string
+	buildRevision string
+	buildTime     string // RFC 3339
+)
+
+// WithBuildInfo is an Option that registers the BuildInfo of the program as
+// the read-only bean BuildInfoBean. It's read from debug.ReadBuildInfo, then
+// from the variables the linker set, then from the non-zero fields of
+// override, each taking precedence over the former.
+func WithBuildInfo(override BuildInfo) Option {
+	return optionFunc(func(c *Container) {
+		info := readBuildInfo()
+		info.merge(override)
+		c.buildInfo = &info
+	})
+}
+
+// registerBuiltins registers the beans of the options, by New and Reset.
+func (c *Container) registerBuiltins() {
+	if c.buildInfo != nil {
+		c.register(c.buildInfo, registerOptions{Name: BuildInfoBean, ReadOnly: true})
+	}
+}
+
+// readBuildInfo reads the BuildInfo of the program and the variables the
+// linker set.
+func readBuildInfo() BuildInfo {
+	info := BuildInfo{GoVersion: runtime.Version()}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		info.Path = bi.Main.Path
+		if bi.Main.Version != "(devel)" {
+			info.Version = bi.Main.Version
+		}
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				info.Revision = s.Value
+			case "vcs.time":
+				info.Time, _ = time.Parse(time.RFC3339, s.Value)
+			case "vcs.modified":
+				info.Modified = s.Value == "true"
+			}
+		}
+	}
+	linked := BuildInfo{Version: buildVersion, Revision: buildRevision}
+	linked.Time, _ = time.Parse(time.RFC3339, buildTime)
+	info.merge(linked)
+	return info
+}
+
+// merge sets the non-zero fields of override into info.
+func (info *BuildInfo) merge(override BuildInfo) {
+	if override.Path != "" {
+		info.Path = override.Path
+	}
+	if override.Version != "" {
+		info.Version = override.Version
+	}
+	if override.Revision != "" {
+		info.Revision = override.Revision
+	}
+	if !override.Time.IsZero() {
+		info.Time = override.Time
+	}
+	if override.Modified {
+		info.Modified = true
+	}
+	if override.GoVersion != "" {
+		info.GoVersion = override.GoVersion
+	}
+}
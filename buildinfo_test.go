@@ -0,0 +1,45 @@
+package keeper
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+type versionHandler struct {
+	build *BuildInfo `name:"buildInfo"`
+}
+
+func TestWithBuildInfo(t *testing.T) {
+	unsafeOnly(t)
+	c := New(WithBuildInfo(BuildInfo{Version: "v1.2.3"}))
+	h := &versionHandler{}
+	if err := c.Register(h, Name("version")); err != nil {
+		t.Fatal(err)
+	}
+	if h.build == nil || h.build.Version != "v1.2.3" {
+		t.Fatalf("build = %+v, want version v1.2.3", h.build)
+	}
+	if h.build.GoVersion != runtime.Version() {
+		t.Errorf("GoVersion = %q, want %q", h.build.GoVersion, runtime.Version())
+	}
+	if _, err := c.Swap(BuildInfoBean, &BuildInfo{}); err == nil {
+		t.Error("Swap of the read-only buildInfo succeeded")
+	}
+	if err := c.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if c.Find(BuildInfoBean) == nil {
+		t.Error("buildInfo is gone after Reset")
+	}
+}
+
+func TestBuildInfoMerge(t *testing.T) {
+	at := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	info := BuildInfo{Version: "v1.0.0", Revision: "abc", GoVersion: "go1.21"}
+	info.merge(BuildInfo{Version: "v2.0.0", Time: at})
+	want := BuildInfo{Version: "v2.0.0", Revision: "abc", Time: at, GoVersion: "go1.21"}
+	if info != want {
+		t.Errorf("merge = %+v, want %+v", info, want)
+	}
+}
@@ -0,0 +1,78 @@
+package keeper
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrMissingCapability is returned when a sensitive bean is about to be
+// injected into a bean that doesn't hold the required capability.
+var ErrMissingCapability = errors.New("missing capability")
+
+// RequireCapability is a RegisterOption that marks the bean as sensitive, like
+// credentials or signing keys: it's only injected into beans registered with
+// the capability granted by Capabilities.
+func RequireCapability(capability string) RegisterOption {
+	return registerOptionFunc(func(options *registerOptions) {
+		options.Requires = append(options.Requires, capability)
+	})
+}
+
+// Capabilities is a RegisterOption that grants the bean capabilities, which
+// allow sensitive beans to be injected into it.
+func Capabilities(capabilities ...string) RegisterOption {
+	return registerOptionFunc(func(options *registerOptions) {
+		options.Grants = append(options.Grants, capabilities...)
+	})
+}
+
+type deniedType struct {
+	typ        reflect.Type
+	capability string
+}
+
+// DenyTypes is an Option that requires capability for injecting any bean of
+// the types of samples, whatever options it's registered with. A sample of a
+// pointer to interface, like (*Signer)(nil), denies every bean implementing it.
+func DenyTypes(capability string, samples ...interface{}) Option {
+	return optionFunc(func(c *Container) {
+		for _, sample := range samples {
+			typ := reflect.TypeOf(sample)
+			if typ.Kind() == reflect.Ptr && typ.Elem().Kind() == reflect.Interface {
+				typ = typ.Elem()
+			}
+			c.deniedTypes = append(c.deniedTypes, deniedType{typ: typ, capability: capability})
+		}
+	})
+}
+
+// checkCapability verifies that the bean registered with options holds the
+// capabilities required to get the bean of name injected. Beans loaded by
+// Provider hold none.
+func (c *Container) checkCapability(name string, bean interface{}, options registerOptions) error {
+	c.mu.RLock()
+	required := c.opts[name].Requires
+	c.mu.RUnlock()
+	typ := reflect.TypeOf(bean)
+	for _, d := range c.deniedTypes {
+		if typ == d.typ || (d.typ.Kind() == reflect.Interface && typ.Implements(d.typ)) {
+			required = append(required[:len(required):len(required)], d.capability)
+		}
+	}
+	for _, capability := range required {
+		if !hasCapability(options.Grants, capability) {
+			return fmt.Errorf("%w %q", ErrMissingCapability, capability)
+		}
+	}
+	return nil
+}
+
+func hasCapability(grants []string, capability string) bool {
+	for _, g := range grants {
+		if g == capability {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package keeper
+
+import (
+	"errors"
+	"testing"
+)
+
+type Signer interface {
+	Sign(data []byte) []byte
+}
+
+type hmacKey struct{}
+
+func (*hmacKey) Sign(data []byte) []byte { return data }
+
+type tokenIssuer struct {
+	signer Signer `name:"signer"`
+}
+
+type credentials struct{}
+
+type dbClient struct {
+	creds *credentials `name:"dbCredentials"`
+}
+
+func TestContainer_RequireCapability(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	c.Register(new(credentials), Name("dbCredentials"), RequireCapability("secrets"))
+	err := c.Register(new(dbClient), Name("untrusted"))
+	if !errors.Is(err, ErrMissingCapability) {
+		t.Fatalf("got %v, want ErrMissingCapability", err)
+	}
+	if err := c.Register(new(dbClient), Name("db"), Capabilities("secrets")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Provider(new(dbClient)); !errors.Is(err, ErrMissingCapability) {
+		t.Fatalf("got %v, want ErrMissingCapability", err)
+	}
+}
+
+func TestContainer_DenyTypes(t *testing.T) {
+	unsafeOnly(t)
+	c := New(DenyTypes("signing", (*Signer)(nil)))
+	c.Register(new(hmacKey), Name("signer"))
+	if err := c.Register(new(tokenIssuer), Name("issuer")); !errors.Is(err, ErrMissingCapability) {
+		t.Fatalf("got %v, want ErrMissingCapability", err)
+	}
+	if err := c.Register(new(tokenIssuer), Name("trustedIssuer"), Capabilities("signing")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type noopSigner struct{}
+
+func (noopSigner) Sign(data []byte) []byte { return data }
+
+func TestContainer_DenyTypesSwap(t *testing.T) {
+	unsafeOnly(t)
+	c := New(DenyTypes("signing", (*hmacKey)(nil)))
+	c.Register(noopSigner{}, Name("signer"))
+	issuer := new(tokenIssuer)
+	if err := c.Register(issuer, Name("issuer")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Swap("signer", new(hmacKey)); !errors.Is(err, ErrMissingCapability) {
+		t.Fatalf("got %v, want ErrMissingCapability", err)
+	}
+	if _, ok := issuer.signer.(noopSigner); !ok {
+		t.Fatalf("issuer got %T injected", issuer.signer)
+	}
+}
+
+type lazyDBClient struct {
+	creds Lazy[*credentials] `name:"dbCredentials"`
+}
+
+func TestContainer_CapabilityBypass(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	c.Register(new(credentials), Name("dbCredentials"), RequireCapability("secrets"))
+
+	untrusted, trusted := new(lazyDBClient), new(lazyDBClient)
+	c.Register(untrusted, Name("untrusted"))
+	c.Register(trusted, Name("trusted"), Capabilities("secrets"))
+	if _, err := untrusted.creds.Get(); !errors.Is(err, ErrMissingCapability) {
+		t.Fatalf("Lazy.Get = %v, want ErrMissingCapability", err)
+	}
+	if _, err := trusted.creds.Get(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Resolve[*credentials](c); !errors.Is(err, ErrMissingCapability) {
+		t.Fatalf("ResolveType = %v, want ErrMissingCapability", err)
+	}
+	if _, err := BindFunc(c, func(*credentials) {}); !errors.Is(err, ErrMissingCapability) {
+		t.Fatalf("BindFunc = %v, want ErrMissingCapability", err)
+	}
+	c.ProvideConstructor(func(creds *credentials) *dbClient { return &dbClient{creds: creds} })
+	if _, err := Resolve[*dbClient](c); !errors.Is(err, ErrMissingCapability) {
+		t.Fatalf("constructor = %v, want ErrMissingCapability", err)
+	}
+}
@@ -0,0 +1,114 @@
+package keeper
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Middleware is a RegisterOption through which the bean contributes fn to
+// the Chain of H, like an authentication or a tracing middleware of the
+// http.Handler chain. The chain runs the middleware by ascending order, the
+// lowest one outermost, and the ones of the same order in registration
+// order. A bean may contribute several middleware, to several chains:
+//
+//	c.Register(auth, keeper.Name("auth"), keeper.Middleware(10, auth.Wrap))
+func Middleware[H any](order int, fn func(H) H) RegisterOption {
+	return registerOptionFunc(func(options *registerOptions) {
+		options.Middleware = append(options.Middleware, middlewareFunc{order: order, fn: fn})
+	})
+}
+
+// middlewareFunc is a func(H) H contributed by Middleware.
+type middlewareFunc struct {
+	order int
+	fn    interface{}
+}
+
+// Chain is the middleware of H contributed by the beans, assembled by order.
+// Fields of a Chain type are injected without a tag, with the middleware of
+// the beans registered so far, which become dependencies of the bean:
+//
+//	type Server struct {
+//		middleware keeper.Chain[http.Handler]
+//	}
+//
+//	http.ListenAndServe(addr, s.middleware.Then(mux))
+type Chain[H any] []func(H) H
+
+// Then returns h wrapped by the middleware of the chain, the first one
+// outermost.
+func (ch Chain[H]) Then(h H) H {
+	for i := len(ch) - 1; i >= 0; i-- {
+		h = ch[i](h)
+	}
+	return h
+}
+
+// ChainOf returns the Chain of H assembled from the beans of k, for
+// consumers assembling it once every bean is registered, like on Start.
+func ChainOf[H any](k Keeper) (Chain[H], error) {
+	var consumer struct {
+		chain Chain[H]
+	}
+	err := k.Provider(&consumer)
+	return consumer.chain, err
+}
+
+func (Chain[H]) assemble(fns []interface{}) interface{} {
+	ch := make(Chain[H], len(fns))
+	for i, fn := range fns {
+		ch[i] = fn.(func(H) H)
+	}
+	return ch
+}
+
+// chainField is implemented by the Chain types.
+type chainField interface {
+	assemble(fns []interface{}) interface{}
+}
+
+var _chainFieldType = reflect.TypeOf((*chainField)(nil)).Elem()
+
+// setChain sets the i-th field of the struct ptr points to, a Chain, if it
+// isn't set already, and records the contributors as dependencies.
+func (c *Container) setChain(ptr interface{}, i int, w *wiring) error {
+	fv := reflect.ValueOf(ptr).Elem().Field(i)
+	if !fv.IsNil() {
+		return nil
+	}
+	fnType := fv.Type().Elem()
+	type contribution struct {
+		middlewareFunc
+		bean string
+	}
+	var contributions []contribution
+	c.mu.RLock()
+	for _, name := range c.order {
+		for _, mw := range c.opts[name].Middleware {
+			if reflect.TypeOf(mw.fn) == fnType {
+				contributions = append(contributions, contribution{mw, name})
+			}
+		}
+	}
+	c.mu.RUnlock()
+	sort.SliceStable(contributions, func(a, b int) bool {
+		return contributions[a].order < contributions[b].order
+	})
+	fns := make([]interface{}, len(contributions))
+	contributors := make(map[string]bool)
+	for j, ct := range contributions {
+		fns[j] = ct.fn
+		if !contributors[ct.bean] {
+			contributors[ct.bean] = true
+			w.deps = append(w.deps, ct.bean)
+		}
+	}
+	chain := reflect.Zero(fv.Type()).Interface().(chainField).assemble(fns)
+	fv, err := settable(fv)
+	if err != nil {
+		return fmt.Errorf("failed to set chain %s: %v", fieldPath(ptr, i), err)
+	}
+	fv.Set(reflect.ValueOf(chain))
+	return nil
+}
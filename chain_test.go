@@ -0,0 +1,55 @@
+package keeper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type headerTagger struct {
+	value string
+}
+
+func (t *headerTagger) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("X-Chain", t.value)
+		next.ServeHTTP(w, r)
+	})
+}
+
+type chainServer struct {
+	middleware Chain[http.Handler]
+}
+
+func TestChain(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	inner, outer, last := &headerTagger{"inner"}, &headerTagger{"outer"}, &headerTagger{"last"}
+	c.Register(inner, Name("inner"), Middleware(20, inner.Wrap))
+	c.Register(last, Name("last"), Middleware(20, last.Wrap))
+	c.Register(outer, Name("outer"), Middleware(10, outer.Wrap))
+	c.Register(&HelloSrv{}, Name("unrelated"), Middleware(0, func(s string) string { return s }))
+
+	srv := new(chainServer)
+	if err := c.Register(srv, Name("server")); err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	srv.middleware.Then(http.NotFoundHandler()).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if got := rec.Header()["X-Chain"]; len(got) != 3 || got[0] != "outer" || got[1] != "inner" || got[2] != "last" {
+		t.Fatalf("middleware ran in the order %v", got)
+	}
+	for _, b := range c.Beans() {
+		if b.Name == "server" && len(b.Dependencies) != 3 {
+			t.Fatalf("got dependencies %v, want the 3 contributors", b.Dependencies)
+		}
+	}
+
+	chain, err := ChainOf[string](c.View())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain) != 1 || chain.Then("x") != "x" {
+		t.Fatalf("got chain of %d middleware", len(chain))
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/tooky0630/keeper"
+)
+
+// manifest describes the beans of a container.
+type manifest struct {
+	Package   string    `json:"package"`
+	Type      string    `json:"type"`
+	Imports   []string  `json:"imports"`
+	Beans     []bean    `json:"beans"`
+	Module    string    `json:"module"`    // name given to keeper.RegisterModule, defaults to the package
+	Factories []factory `json:"factories"` // registered by the generated module
+
+	NilObjects []string `json:"nilObjects"` // interfaces of the package given nil objects
+}
+
+// factory is a constructor of the form func(keeper.Keeper) (T, error).
+type factory struct {
+	Name        string `json:"name"` // defaults to keeper.BeanName of the constructor without its New prefix
+	Constructor string `json:"constructor"`
+}
+
+type bean struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	Method string `json:"method"` // defaults to the exported bean name
+}
+
+var facadeTmpl = template.Must(template.New("facade").Parse(`// Code generated by keepergen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"github.com/tooky0630/keeper"
+{{range .Imports}}	"{{.}}"
+{{end}})
+
+// {{.Type}} gives typed access to the beans of a keeper container.
+type {{.Type}} struct {
+	keeper.Keeper
+}
+{{range .Beans}}
+// {{.Method}} returns the bean registered as "{{.Name}}".
+func (a *{{$.Type}}) {{.Method}}() {{.Type}} {
+	return a.Find("{{.Name}}").({{.Type}})
+}
+{{end}}`))
+
+var moduleTmpl = template.Must(template.New("module").Parse(`// Code generated by keepergen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"github.com/tooky0630/keeper"
+{{range .Imports}}	"{{.}}"
+{{end}})
+
+func init() {
+	keeper.RegisterModule("{{.Module}}", keeper.ModuleFunc(KeeperModule))
+}
+
+// KeeperModule registers the factories of package {{.Package}}.
+func KeeperModule(k keeper.Keeper) error {
+{{- range .Factories}}
+	if err := k.RegisterFactory(func(k keeper.Keeper) (interface{}, error) {
+		return {{.Constructor}}(k)
+	}, keeper.Name("{{.Name}}"), keeper.GeneratedBy("keepergen")); err != nil {
+		return err
+	}
+{{- end}}
+	return nil
+}
+`))
+
+var typesTmpl = template.Must(template.New("types").Parse(`// Code generated by keepergen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"reflect"
+{{range .Imports}}	"{{.}}"
+{{end}})
+
+// {{.Type}}Types are the types of the beans of a keeper container, checked
+// by keeper.Keeper.AssertTypes.
+var {{.Type}}Types = map[string]reflect.Type{
+{{- range .Beans}}
+	"{{.Name}}": reflect.TypeOf((*{{.Type}})(nil)).Elem(),
+{{- end}}
+}
+`))
+
+// generateTypes renders the types source of m.
+func generateTypes(m manifest) ([]byte, error) {
+	if m.Package == "" {
+		return nil, fmt.Errorf("manifest has no package")
+	}
+	for i, b := range m.Beans {
+		if b.Name == "" || b.Type == "" {
+			return nil, fmt.Errorf("bean %d must have a name and a type", i)
+		}
+	}
+	var buf bytes.Buffer
+	if err := typesTmpl.Execute(&buf, m); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// generateModule renders the module source of m.
+func generateModule(m manifest) ([]byte, error) {
+	if m.Package == "" {
+		return nil, fmt.Errorf("manifest has no package")
+	}
+	if m.Module == "" {
+		m.Module = m.Package
+	}
+	for i, f := range m.Factories {
+		if f.Constructor == "" {
+			return nil, fmt.Errorf("factory %d must have a constructor", i)
+		}
+		if f.Name == "" {
+			m.Factories[i].Name = keeper.BeanName(strings.TrimPrefix(f.Constructor, "New"))
+		}
+	}
+	var buf bytes.Buffer
+	if err := moduleTmpl.Execute(&buf, m); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// generate renders the facade source of m.
+func generate(m manifest) ([]byte, error) {
+	if m.Package == "" {
+		return nil, fmt.Errorf("manifest has no package")
+	}
+	for i, b := range m.Beans {
+		if b.Name == "" || b.Type == "" {
+			return nil, fmt.Errorf("bean %d must have a name and a type", i)
+		}
+		if b.Method == "" {
+			m.Beans[i].Method = exported(b.Name)
+		}
+	}
+	var buf bytes.Buffer
+	if err := facadeTmpl.Execute(&buf, m); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// exported turns a bean name like "hello-service" into a Go identifier like "HelloService".
+func exported(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
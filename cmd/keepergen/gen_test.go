@@ -0,0 +1,82 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	src, err := generate(manifest{
+		Package: "app",
+		Type:    "App",
+		Imports: []string{"example.com/app/hello"},
+		Beans: []bean{
+			{Name: "helloService", Type: "*hello.HelloSrv"},
+			{Name: "hello-ctl", Type: "*hello.HelloCtl"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"func (a *App) HelloService() *hello.HelloSrv {",
+		`return a.Find("hello-ctl").(*hello.HelloCtl)`,
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Fatalf("missing %q in\n%s", want, src)
+		}
+	}
+}
+
+func TestGenerateInvalid(t *testing.T) {
+	if _, err := generate(manifest{Package: "app", Beans: []bean{{Name: "x"}}}); err == nil {
+		t.Fatal("expected error for bean without type")
+	}
+}
+
+func TestGenerateModule(t *testing.T) {
+	src, err := generateModule(manifest{
+		Package: "pb",
+		Factories: []factory{
+			{Constructor: "NewGreeterServer"},
+			{Name: "health", Constructor: "health.NewServer"},
+		},
+		Imports: []string{"example.com/app/health"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`keeper.RegisterModule("pb", keeper.ModuleFunc(KeeperModule))`,
+		"return NewGreeterServer(k)",
+		`keeper.Name("greeterServer"), keeper.GeneratedBy("keepergen")`,
+		`keeper.Name("health")`,
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Fatalf("missing %q in\n%s", want, src)
+		}
+	}
+	if _, err := generateModule(manifest{Package: "pb", Factories: []factory{{}}}); err == nil {
+		t.Fatal("expected error for factory without constructor")
+	}
+}
+
+func TestGenerateTypes(t *testing.T) {
+	src, err := generateTypes(manifest{
+		Package: "app",
+		Type:    "App",
+		Imports: []string{"example.com/app/hello"},
+		Beans:   []bean{{Name: "helloService", Type: "*hello.HelloSrv"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"var AppTypes = map[string]reflect.Type{",
+		`"helloService": reflect.TypeOf((**hello.HelloSrv)(nil)).Elem(),`,
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Fatalf("missing %q in\n%s", want, src)
+		}
+	}
+}
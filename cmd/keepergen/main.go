@@ -0,0 +1,99 @@
+// Command keepergen generates a typed facade over a keeper container, so
+// application code gets its beans from methods instead of Find with strings.
+//
+// The beans are listed in a JSON manifest:
+//
+//	{
+//	  "package": "app",
+//	  "imports": ["example.com/app/hello"],
+//	  "beans": [
+//	    {"name": "helloService", "type": "*hello.HelloSrv"}
+//	  ]
+//	}
+//
+// and
+//
+//	keepergen -manifest keeper.json -o keeper_gen.go
+//
+// emits
+//
+//	func (a *App) HelloService() *hello.HelloSrv
+//
+// With -mode module, keepergen is the reference generator of the keeper
+// registrations of generated code: it emits a KeeperModule registering the
+// factories of the manifest, whose constructors take the keeper.Keeper,
+//
+//	{
+//	  "package": "pb",
+//	  "factories": [
+//	    {"constructor": "NewGreeterServer"}
+//	  ]
+//	}
+//
+// under their keeper.BeanName, "greeterServer", and installs it with
+// keeper.RegisterModule from an init function.
+//
+// With -mode types, keepergen emits the map of the types of the beans, AppTypes,
+// for keeper.Keeper.AssertTypes to check the container at startup.
+//
+// With -mode nilobjects, keepergen emits the nil objects of the interfaces
+// the manifest lists as "nilObjects", which the Go files next to it declare:
+// methods returning zero values, and keeper.ErrDisabled as their error,
+// registered by keeper.RegisterNilObject for keeper.NilObjectFor.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	manifestPath := flag.String("manifest", "keeper.json", "manifest listing the beans")
+	output := flag.String("o", "", "output file, stdout if empty")
+	typeName := flag.String("type", "App", "name of the generated facade type")
+	mode := flag.String("mode", "facade", "what to generate: facade, module, types or nilobjects")
+	flag.Parse()
+
+	data, err := ioutil.ReadFile(*manifestPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		log.Fatalf("parse %s: %v", *manifestPath, err)
+	}
+	if m.Type == "" {
+		m.Type = *typeName
+	}
+	var src []byte
+	switch *mode {
+	case "facade":
+		src, err = generate(m)
+	case "module":
+		src, err = generateModule(m)
+	case "types":
+		src, err = generateTypes(m)
+	case "nilobjects":
+		fset, files, perr := parseDir(filepath.Dir(*manifestPath))
+		if perr != nil {
+			log.Fatal(perr)
+		}
+		src, err = generateNilObjects(m, fset, files)
+	default:
+		log.Fatalf("unknown mode %q", *mode)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *output == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := ioutil.WriteFile(*output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
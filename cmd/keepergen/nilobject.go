@@ -0,0 +1,242 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// parseDir parses the Go files of the package in dir, tests left out.
+func parseDir(dir string) (*token.FileSet, []*ast.File, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, nil, err
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		f, err := parser.ParseFile(fset, path, src, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, nil, err
+		}
+		files = append(files, f)
+	}
+	return fset, files, nil
+}
+
+// declaredInterface is an interface declared in a file of the package.
+type declaredInterface struct {
+	file *ast.File
+	typ  *ast.InterfaceType
+}
+
+// generateNilObjects renders the nil objects of the interfaces m.NilObjects
+// names, which files declare, registered by keeper.RegisterNilObject.
+func generateNilObjects(m manifest, fset *token.FileSet, files []*ast.File) ([]byte, error) {
+	if m.Package == "" {
+		return nil, fmt.Errorf("manifest has no package")
+	}
+	declared := make(map[string]declaredInterface)
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if it, ok := ts.Type.(*ast.InterfaceType); ok && ts.TypeParams == nil {
+					declared[ts.Name.Name] = declaredInterface{file: f, typ: it}
+				}
+			}
+		}
+	}
+	g := nilObjectGen{fset: fset, declared: declared, imports: make(map[string]bool)}
+	var body bytes.Buffer
+	var inits []string
+	for _, name := range m.NilObjects {
+		methods, err := g.methods(name, nil)
+		if err != nil {
+			return nil, err
+		}
+		obj := "nil" + name
+		fmt.Fprintf(&body, "\n// %s is the nil object of %s, see keeper.NilObjectFor.\ntype %s struct{}\n", obj, name, obj)
+		for _, m := range methods {
+			g.method(&body, obj, m)
+		}
+		inits = append(inits, fmt.Sprintf("\tkeeper.RegisterNilObject((*%s)(nil), %s{})\n", name, obj))
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by keepergen. DO NOT EDIT.\n\npackage %s\n\nimport (\n", m.Package)
+	std, others := []string{}, []string{`"github.com/tooky0630/keeper"`}
+	for spec := range g.imports {
+		if path := spec[strings.IndexByte(spec, '"'):]; strings.Contains(strings.SplitN(path, "/", 2)[0], ".") {
+			others = append(others, spec)
+		} else {
+			std = append(std, spec)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(others)
+	for _, spec := range std {
+		fmt.Fprintf(&buf, "\t%s\n", spec)
+	}
+	if len(std) > 0 {
+		buf.WriteString("\n")
+	}
+	for _, spec := range others {
+		fmt.Fprintf(&buf, "\t%s\n", spec)
+	}
+	buf.WriteString(")\n\nfunc init() {\n")
+	for _, line := range inits {
+		buf.WriteString(line)
+	}
+	buf.WriteString("}\n")
+	buf.Write(body.Bytes())
+	return format.Source(buf.Bytes())
+}
+
+type nilObjectGen struct {
+	fset     *token.FileSet
+	declared map[string]declaredInterface
+	imports  map[string]bool // import specs used by the methods
+}
+
+// declaredMethod is a method of an interface, with the file declaring it.
+type declaredMethod struct {
+	name string
+	typ  *ast.FuncType
+	file *ast.File
+}
+
+// methods lists the methods of the interface name, those of the interfaces
+// it embeds included; seen guards against embedding cycles.
+func (g *nilObjectGen) methods(name string, seen map[string]bool) ([]declaredMethod, error) {
+	iface, ok := g.declared[name]
+	if !ok {
+		return nil, fmt.Errorf("interface %s isn't declared in the package", name)
+	}
+	if seen[name] {
+		return nil, fmt.Errorf("interface %s embeds itself", name)
+	}
+	if seen == nil {
+		seen = make(map[string]bool)
+	}
+	seen[name] = true
+	var methods []declaredMethod
+	for _, field := range iface.typ.Methods.List {
+		if len(field.Names) == 0 {
+			embedded, ok := field.Type.(*ast.Ident)
+			if !ok {
+				return nil, fmt.Errorf("interface %s embeds %s, only interfaces of the package are supported", name, g.expr(field.Type))
+			}
+			inner, err := g.methods(embedded.Name, seen)
+			if err != nil {
+				return nil, err
+			}
+			methods = append(methods, inner...)
+			continue
+		}
+		for _, n := range field.Names {
+			methods = append(methods, declaredMethod{name: n.Name, typ: field.Type.(*ast.FuncType), file: iface.file})
+		}
+	}
+	return methods, nil
+}
+
+// method renders the method m of the nil object obj: its results are zero,
+// but for a last error result, keeper.ErrDisabled.
+func (g *nilObjectGen) method(w *bytes.Buffer, obj string, m declaredMethod) {
+	var params []string
+	if m.typ.Params != nil {
+		for _, p := range m.typ.Params.List {
+			typ := g.typeOf(p.Type, m.file)
+			for i := 0; i < len(p.Names) || i == 0; i++ {
+				params = append(params, typ)
+			}
+		}
+	}
+	var results []string
+	if m.typ.Results != nil {
+		for _, r := range m.typ.Results.List {
+			typ := g.typeOf(r.Type, m.file)
+			for i := 0; i < len(r.Names) || i == 0; i++ {
+				results = append(results, fmt.Sprintf("r%d %s", len(results), typ))
+			}
+		}
+	}
+	fmt.Fprintf(w, "\nfunc (%s) %s(%s) ", obj, m.name, strings.Join(params, ", "))
+	if len(results) > 0 {
+		fmt.Fprintf(w, "(%s) ", strings.Join(results, ", "))
+	}
+	w.WriteString("{\n")
+	if n := len(results); n > 0 {
+		if strings.HasSuffix(results[n-1], " error") {
+			fmt.Fprintf(w, "\tr%d = keeper.ErrDisabled\n", n-1)
+		}
+		w.WriteString("\treturn\n")
+	}
+	w.WriteString("}\n")
+}
+
+// typeOf renders the type expression expr of file, recording the imports
+// of file it uses.
+func (g *nilObjectGen) typeOf(expr ast.Expr, file *ast.File) string {
+	ast.Inspect(expr, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if pkg, ok := sel.X.(*ast.Ident); ok {
+			if spec := importOf(file, pkg.Name); spec != "" {
+				g.imports[spec] = true
+			}
+		}
+		return false
+	})
+	return g.expr(expr)
+}
+
+func (g *nilObjectGen) expr(expr ast.Expr) string {
+	var buf bytes.Buffer
+	printer.Fprint(&buf, g.fset, expr)
+	return buf.String()
+}
+
+// importOf returns the import spec of file for the package named pkg, its
+// name being the last element of its path unless it's renamed.
+func importOf(file *ast.File, pkg string) string {
+	for _, imp := range file.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		if imp.Name != nil {
+			if imp.Name.Name == pkg {
+				return imp.Name.Name + " " + imp.Path.Value
+			}
+			continue
+		}
+		if path[strings.LastIndexByte(path, '/')+1:] == pkg {
+			return imp.Path.Value
+		}
+	}
+	return ""
+}
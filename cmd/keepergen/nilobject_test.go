@@ -0,0 +1,69 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+const featureSrc = `package billing
+
+import (
+	"context"
+	tm "time"
+)
+
+type Closer interface {
+	Close() error
+}
+
+type Billing interface {
+	Closer
+	Charge(ctx context.Context, account string, cents int64) (id string, err error)
+	Due(at tm.Time, accounts ...string) int
+	Reset()
+}
+`
+
+func TestGenerateNilObjects(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "billing.go", featureSrc, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := generateNilObjects(manifest{Package: "billing", NilObjects: []string{"Billing"}}, fset, []*ast.File{f})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"\t\"context\"\n",
+		"\ttm \"time\"\n",
+		"keeper.RegisterNilObject((*Billing)(nil), nilBilling{})",
+		"func (nilBilling) Close() (r0 error) {\n\tr0 = keeper.ErrDisabled\n\treturn\n}",
+		"func (nilBilling) Charge(context.Context, string, int64) (r0 string, r1 error) {\n\tr1 = keeper.ErrDisabled\n",
+		"func (nilBilling) Due(tm.Time, ...string) (r0 int) {\n\treturn\n}",
+		"func (nilBilling) Reset() {\n}",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("missing %q in\n%s", want, src)
+		}
+	}
+}
+
+func TestGenerateNilObjectsErrors(t *testing.T) {
+	fset := token.NewFileSet()
+	f, _ := parser.ParseFile(fset, "io.go", "package p\nimport \"io\"\ntype Stream interface { io.Reader }\n", 0)
+	for _, tt := range []struct {
+		iface, want string
+	}{
+		{"Missing", "interface Missing isn't declared in the package"},
+		{"Stream", "interface Stream embeds io.Reader, only interfaces of the package are supported"},
+	} {
+		_, err := generateNilObjects(manifest{Package: "p", NilObjects: []string{tt.iface}}, fset, []*ast.File{f})
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("got %v, want %q", err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// explorer navigates a graph by commands, printing to out.
+type explorer struct {
+	g       *graph
+	out     io.Writer
+	current string
+	history []string
+	listed  []string // beans numbered by the last output
+}
+
+func newExplorer(g *graph, out io.Writer) *explorer {
+	return &explorer{g: g, out: out}
+}
+
+func (e *explorer) prompt() string {
+	if e.current == "" {
+		return "> "
+	}
+	return e.current + "> "
+}
+
+// exec runs the command of line, and reports whether to quit.
+func (e *explorer) exec(line string) bool {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return false
+	}
+	cmd, args := fields[0], fields[1:]
+	if n, err := strconv.Atoi(cmd); err == nil {
+		if n < 1 || n > len(e.listed) {
+			fmt.Fprintf(e.out, "no bean %d\n", n)
+			return false
+		}
+		e.visit(e.listed[n-1])
+		return false
+	}
+	switch cmd {
+	case "ls":
+		pattern := "*"
+		if len(args) > 0 {
+			pattern = args[0]
+		}
+		e.ls(pattern)
+	case "cd":
+		if len(args) != 1 {
+			fmt.Fprintln(e.out, "usage: cd <bean>")
+			return false
+		}
+		e.visit(args[0])
+	case "back":
+		if len(e.history) == 0 {
+			fmt.Fprintln(e.out, "no previous bean")
+			return false
+		}
+		prev := e.history[len(e.history)-1]
+		e.history = e.history[:len(e.history)-1]
+		e.show(prev)
+	case "slow":
+		limit := 10
+		if len(args) > 0 {
+			if n, err := strconv.Atoi(args[0]); err == nil && n > 0 {
+				limit = n
+			}
+		}
+		e.slow(limit)
+	case "help", "?":
+		fmt.Fprint(e.out, _help)
+	case "quit", "exit", "q":
+		return true
+	default:
+		fmt.Fprintf(e.out, "unknown command %q, try help\n", cmd)
+	}
+	return false
+}
+
+const _help = `ls [pattern]   list the beans, optionally matching a path.Match pattern
+cd <bean>      show a bean
+<n>            show the n-th bean listed
+back           show the previous bean
+slow [n]       list the n beans slowest to build
+quit           exit
+`
+
+// visit shows the bean of name, remembering the current one for back.
+func (e *explorer) visit(name string) {
+	if _, ok := e.g.nodes[name]; !ok {
+		fmt.Fprintf(e.out, "no bean %q\n", name)
+		return
+	}
+	if e.current != "" {
+		e.history = append(e.history, e.current)
+	}
+	e.show(name)
+}
+
+func (e *explorer) show(name string) {
+	n := e.g.nodes[name]
+	e.current = name
+	e.listed = e.listed[:0]
+	fmt.Fprintf(e.out, "%s %s\n", n.name, n.typ)
+	if n.site != "" {
+		fmt.Fprintf(e.out, "  registered at %s\n", n.site)
+	}
+	if n.build > 0 {
+		fmt.Fprintf(e.out, "  built in %v\n", n.build)
+	}
+	keys := make([]string, 0, len(n.labels))
+	for k := range n.labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(e.out, "  label %s=%s\n", k, n.labels[k])
+	}
+	e.links("depends on", n.deps)
+	e.links("used by", n.dependents)
+}
+
+// links prints the beans of names numbered after those already listed.
+func (e *explorer) links(title string, names []string) {
+	if len(names) == 0 {
+		return
+	}
+	fmt.Fprintf(e.out, "  %s:\n", title)
+	for _, name := range names {
+		e.listed = append(e.listed, name)
+		if _, ok := e.g.nodes[name]; !ok {
+			name += " (external)"
+		}
+		fmt.Fprintf(e.out, "  %3d  %s\n", len(e.listed), name)
+	}
+}
+
+func (e *explorer) ls(pattern string) {
+	e.listed = e.listed[:0]
+	for _, name := range e.g.names {
+		if ok, err := path.Match(pattern, name); err != nil {
+			fmt.Fprintf(e.out, "invalid pattern %q: %v\n", pattern, err)
+			return
+		} else if !ok {
+			continue
+		}
+		e.listed = append(e.listed, name)
+		fmt.Fprintf(e.out, "%3d  %-30s %s\n", len(e.listed), name, e.g.nodes[name].typ)
+	}
+}
+
+func (e *explorer) slow(limit int) {
+	var built []*node
+	for _, name := range e.g.names {
+		if n := e.g.nodes[name]; n.build > 0 {
+			built = append(built, n)
+		}
+	}
+	if len(built) == 0 {
+		fmt.Fprintln(e.out, "no init timings, run the container with keeper.AccountResources")
+		return
+	}
+	sort.SliceStable(built, func(i, j int) bool { return built[i].build > built[j].build })
+	if len(built) > limit {
+		built = built[:limit]
+	}
+	e.listed = e.listed[:0]
+	for _, n := range built {
+		e.listed = append(e.listed, n.name)
+		fmt.Fprintf(e.out, "%3d  %-30s %v\n", len(e.listed), n.name, n.build.Round(time.Microsecond))
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/tooky0630/keeper"
+)
+
+type store struct{}
+
+type service struct {
+	Store *store `name:"store"`
+}
+
+type handler struct {
+	Service *service `name:"service"`
+}
+
+func exampleContainer(t *testing.T) keeper.Keeper {
+	c := keeper.New(keeper.AccountResources())
+	for _, reg := range []keeper.Registration{
+		keeper.Bean(new(store), keeper.Name("store")),
+		keeper.Bean(new(service), keeper.Name("service")),
+		keeper.Bean(new(handler), keeper.Name("handler")),
+	} {
+		if err := c.Register(reg.Bean, reg.Options...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return c
+}
+
+func run(t *testing.T, data []byte, commands ...string) string {
+	g, err := parseGraph(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	e := newExplorer(g, &out)
+	for _, cmd := range commands {
+		e.exec(cmd)
+	}
+	return out.String()
+}
+
+func TestExplorerBeans(t *testing.T) {
+	data, err := json.Marshal(exampleContainer(t).Beans())
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := run(t, data, "cd service", "2", "1", "back", "slow 1")
+	for _, want := range []string{
+		"service *main.service\n",
+		"  depends on:\n    1  store\n  used by:\n    2  handler\n",
+		"handler *main.handler\n",
+		"  built in ",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in\n%s", want, out)
+		}
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if back := lines[len(lines)-6]; back != "handler *main.handler" {
+		t.Errorf("back showed %q", back)
+	}
+	if slowest := lines[len(lines)-1]; !strings.HasPrefix(strings.TrimSpace(slowest), "1  ") {
+		t.Errorf("slow 1 listed %q", slowest)
+	}
+}
+
+func TestExplorerSpec(t *testing.T) {
+	data, err := json.Marshal(exampleContainer(t).ExportSpec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := run(t, data, "ls s*", "cd store", "slow")
+	for _, want := range []string{
+		"  1  store ",
+		"  2  service ",
+		"  used by:\n    1  service\n",
+		"no init timings",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "handler *") {
+		t.Errorf("ls s* listed handler:\n%s", out)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/tooky0630/keeper"
+)
+
+// node is a bean of the graph.
+type node struct {
+	name       string
+	typ        string
+	site       string
+	labels     map[string]string
+	build      time.Duration // zero when unknown
+	deps       []string
+	dependents []string
+}
+
+// graph is the beans of a container, in the order they were listed.
+type graph struct {
+	nodes map[string]*node
+	names []string
+}
+
+// parseGraph parses the JSON array of keeper.BeanInfo served by the admin
+// handler, or a keeper.Spec.
+func parseGraph(data []byte) (*graph, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		spec, err := keeper.ValidateSpec(data)
+		if err != nil {
+			return nil, err
+		}
+		return specGraph(spec), nil
+	}
+	var infos []keeper.BeanInfo
+	if err := json.Unmarshal(data, &infos); err != nil {
+		return nil, err
+	}
+	return infoGraph(infos), nil
+}
+
+func infoGraph(infos []keeper.BeanInfo) *graph {
+	g := &graph{nodes: make(map[string]*node, len(infos))}
+	for _, info := range infos {
+		n := g.add(info.Name)
+		n.typ, n.site, n.labels, n.deps = info.Type, info.Site, info.Labels, info.Dependencies
+		if info.Build != nil {
+			n.build = info.Build.Duration
+		}
+	}
+	g.link()
+	return g
+}
+
+func specGraph(spec *keeper.Spec) *graph {
+	g := &graph{nodes: make(map[string]*node, len(spec.Beans))}
+	for _, bean := range spec.Beans {
+		n := g.add(bean.Name)
+		n.typ, n.labels = bean.Type, bean.Labels
+	}
+	for _, edge := range spec.Edges {
+		g.nodes[edge.From].deps = append(g.nodes[edge.From].deps, edge.To)
+	}
+	g.link()
+	return g
+}
+
+func (g *graph) add(name string) *node {
+	n := &node{name: name}
+	g.nodes[name] = n
+	g.names = append(g.names, name)
+	return n
+}
+
+// link sets the dependents of the nodes from their dependencies.
+func (g *graph) link() {
+	for _, name := range g.names {
+		for _, dep := range g.nodes[name].deps {
+			if d, ok := g.nodes[dep]; ok {
+				d.dependents = append(d.dependents, name)
+			}
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Command keeperx explores the graph of a keeper container in the terminal,
+// which is quicker than reading DOT files to find why a bean got what it got.
+//
+// The graph is read from the /beans endpoint of the admin handler, from a
+// file it was saved to, or from a spec written by keeper.Keeper.ExportSpec,
+// which has no init timings:
+//
+//	keeperx -src http://localhost:6060/debug/keeper/beans
+//	keeperx -src spec.json
+//
+// keeperx then shows a bean with its dependencies and dependents numbered,
+// entering a number goes to that bean. The commands are:
+//
+//	ls [pattern]   list the beans, optionally matching a path.Match pattern
+//	cd <bean>      show a bean
+//	<n>            show the n-th bean listed
+//	back           show the previous bean
+//	slow [n]       list the n beans slowest to build, 10 by default
+//	help           list the commands
+//	quit           exit
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+)
+
+func main() {
+	src := flag.String("src", "", "URL of the /beans endpoint, or file of beans or of a spec")
+	flag.Parse()
+	if *src == "" {
+		log.Fatal("missing -src")
+	}
+	data, err := read(*src)
+	if err != nil {
+		log.Fatal(err)
+	}
+	g, err := parseGraph(data)
+	if err != nil {
+		log.Fatalf("parse %s: %v", *src, err)
+	}
+	e := newExplorer(g, os.Stdout)
+	e.exec("ls")
+	in := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Print(e.prompt())
+		if !in.Scan() || e.exec(in.Text()) {
+			return
+		}
+	}
+}
+
+// read reads the graph from the URL or the file src.
+func read(src string) ([]byte, error) {
+	if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+		return os.ReadFile(src)
+	}
+	resp, err := http.Get(src)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", src, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
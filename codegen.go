@@ -0,0 +1,104 @@
+package keeper
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The hooks below are stable entry points for generated code, like server
+// scaffolding emitted by protoc or openapi plugins: a generator emits a
+// Module registering factories under BeanName names, and registers it with
+// RegisterModule from an init function:
+//
+//	func init() {
+//		keeper.RegisterModule("greeter", keeper.ModuleFunc(KeeperModule))
+//	}
+//
+//	func KeeperModule(k keeper.Keeper) error {
+//		return k.RegisterFactory(func(k keeper.Keeper) (interface{}, error) {
+//			return NewGreeterServer(k)
+//		}, keeper.Name(keeper.BeanName("GreeterServer")), keeper.GeneratedBy("protoc-gen-keeper"))
+//	}
+//
+// The application then installs every generated module:
+//
+//	c.Install(keeper.RegisteredModules()...)
+//
+// cmd/keepergen -mode module is a reference generator.
+
+// _generatedLabel is the label set by GeneratedBy.
+const _generatedLabel = "generated-by"
+
+// GeneratedBy is a RegisterOption marking the bean as emitted by generator,
+// with the "generated-by" label.
+func GeneratedBy(generator string) RegisterOption {
+	return Label(_generatedLabel, generator)
+}
+
+// BeanName is the conventional bean name of a type, its name with a lower
+// case initial: "GreeterServer" and "*pb.GreeterServer" give "greeterServer".
+// Leading acronyms are lowered as a whole, "HTTPClient" gives "httpClient".
+// The type arguments of generic types are kept as they are: "*Repo[model.User]"
+// gives "repo[model.User]".
+func BeanName(typeName string) string {
+	typeName = strings.TrimLeft(typeName, "*")
+	args := ""
+	if i := strings.IndexByte(typeName, '['); i > 0 {
+		typeName, args = typeName[:i], typeName[i:]
+	}
+	if i := strings.LastIndex(typeName, "."); i >= 0 {
+		typeName = typeName[i+1:]
+	}
+	runes := []rune(typeName)
+	n := 0
+	for n < len(runes) && unicode.IsUpper(runes[n]) {
+		n++
+	}
+	if n > 1 && n < len(runes) { // the last upper case letter starts the next word
+		n--
+	}
+	for i := 0; i < n; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	if n == 0 && utf8.RuneCountInString(typeName) > 0 {
+		runes[0] = unicode.ToLower(runes[0])
+	}
+	return string(runes) + args
+}
+
+var (
+	modulesMu sync.Mutex
+	modules   = make(map[string]Module)
+)
+
+// RegisterModule makes m available to RegisteredModules under name, it's
+// meant for the init functions of generated code. It panics when name is
+// registered twice, like the registries of database/sql.
+func RegisterModule(name string, m Module) {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+	if _, dup := modules[name]; dup {
+		panic("keeper: RegisterModule called twice for " + name)
+	}
+	modules[name] = m
+}
+
+// RegisteredModules returns the modules registered by RegisterModule, in
+// order of name.
+func RegisteredModules() []Module {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+	names := make([]string, 0, len(modules))
+	for name := range modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	ms := make([]Module, len(names))
+	for i, name := range names {
+		ms[i] = modules[name]
+	}
+	return ms
+}
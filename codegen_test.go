@@ -0,0 +1,38 @@
+package keeper
+
+import "testing"
+
+func TestBeanName(t *testing.T) {
+	tests := map[string]string{
+		"GreeterServer":     "greeterServer",
+		"*pb.GreeterServer": "greeterServer",
+		"HTTPClient":        "httpClient",
+		"DB":                "db",
+		"helloSrv":          "helloSrv",
+		"":                  "",
+	}
+	for typ, want := range tests {
+		if got := BeanName(typ); got != want {
+			t.Errorf("BeanName(%q) = %q, want %q", typ, got, want)
+		}
+	}
+}
+
+func TestRegisteredModules(t *testing.T) {
+	RegisterModule("test.generated", ModuleFunc(func(k Keeper) error {
+		return k.Register(&HelloSrv{}, Name(BeanName("HelloSrv")), GeneratedBy("keepergen"))
+	}))
+	c := New()
+	if err := c.Install(RegisteredModules()...); err != nil {
+		t.Fatal(err)
+	}
+	if info := c.Beans()[0]; info.Name != "helloSrv" || info.Labels["generated-by"] != "keepergen" {
+		t.Fatalf("got %+v", info)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for a duplicate module")
+		}
+	}()
+	RegisterModule("test.generated", ModuleFunc(func(Keeper) error { return nil }))
+}
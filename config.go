@@ -0,0 +1,288 @@
+package keeper
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ConfigSource provides the values of fields tagged with `value:"key"`.
+type ConfigSource interface {
+	Lookup(key string) (string, bool)
+}
+
+// ConfigWatcher is implemented by a ConfigSource which could report its
+// changes; onChange receives the changed keys, or nil when any key may have changed.
+type ConfigWatcher interface {
+	Watch(onChange func(keys []string))
+}
+
+// Reloadable is implemented by beans which react to configuration changes,
+// OnConfigChange is invoked after their value tagged fields are updated.
+//
+// The fields are updated while the bean may be in use. A bean read by other
+// goroutines must implement sync.Locker, like by embedding a sync.Mutex or a
+// sync.RWMutex: it's locked while its fields are updated, so it must only
+// read them under that lock.
+type Reloadable interface {
+	OnConfigChange()
+}
+
+// WithConfig is an Option that resolves value tagged fields from src, and
+// reloads them whenever src reports a change.
+func WithConfig(src ConfigSource) Option {
+	return optionFunc(func(c *Container) {
+		c.config = src
+		if w, ok := src.(ConfigWatcher); ok {
+			w.Watch(c.reloadConfig)
+		}
+	})
+}
+
+// valueBinding is a value tagged field of a bean.
+type valueBinding struct {
+	bean  string
+	ptr   interface{}
+	field int
+	key   string
+}
+
+// loadValue sets the i-th field of the struct ptr points to from the config.
+func (c *Container) loadValue(ptr interface{}, bean string, i int, tag string, w *wiring) (bool, error) {
+	spec, err := parseTag(tag)
+	if err != nil {
+		return false, err
+	}
+	key := spec.name
+	if !c.profileActive(spec) {
+		raw, ok := spec.option(_defaultOption)
+		if !ok {
+			return false, nil
+		}
+		if err := setField(ptr, i, raw); err != nil {
+			return false, fmt.Errorf("failed to load value %s into %s: %v", key, fieldPath(ptr, i), err)
+		}
+		return true, nil
+	}
+	raw, ok := "", false
+	if c.config != nil {
+		raw, ok = c.config.Lookup(key)
+	}
+	if !ok {
+		raw, ok = spec.option(_defaultOption)
+	}
+	if !ok {
+		if spec.flag(_optionalTag) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to load value %s", key)
+	}
+	if err := setField(ptr, i, raw); err != nil {
+		return false, fmt.Errorf("failed to load value %s into %s: %v", key, fieldPath(ptr, i), err)
+	}
+	if bean != "" {
+		w.values = append(w.values, valueBinding{bean: bean, ptr: ptr, field: i, key: key})
+	}
+	return true, nil
+}
+
+// reloadConfig updates the value tagged fields bound to keys, then notifies
+// the Reloadable beans whose fields were updated.
+func (c *Container) reloadConfig(keys []string) {
+	changed := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		changed[key] = true
+	}
+	c.mu.RLock()
+	values := c.values
+	c.mu.RUnlock()
+	type update struct {
+		valueBinding
+		raw string
+	}
+	var ptrs []interface{} // the beans to update, each under its lock
+	updates := make(map[interface{}][]update)
+	for _, b := range values {
+		if keys != nil && !changed[b.key] {
+			continue
+		}
+		raw, ok := c.config.Lookup(b.key)
+		if !ok {
+			continue
+		}
+		if _, ok := updates[b.ptr]; !ok {
+			ptrs = append(ptrs, b.ptr)
+		}
+		updates[b.ptr] = append(updates[b.ptr], update{b, raw})
+	}
+	reloaded := make(map[string]bool)
+	for _, ptr := range ptrs {
+		if l, ok := ptr.(sync.Locker); ok {
+			l.Lock()
+		}
+		for _, u := range updates[ptr] {
+			if err := setField(u.ptr, u.field, u.raw); err != nil {
+				c.logger.Printf("keeper: failed to reload value %s into %s of %s: %v", u.key, fieldPath(u.ptr, u.field), u.bean, err)
+				continue
+			}
+			reloaded[u.bean] = true
+		}
+		if l, ok := ptr.(sync.Locker); ok {
+			l.Unlock()
+		}
+	}
+	for _, nb := range c.ordered() {
+		if !reloaded[nb.name] {
+			continue
+		}
+		if r, ok := nb.bean.(Reloadable); ok {
+			r.OnConfigChange()
+		}
+	}
+}
+
+// expandSpec expands the ${KEY} variables of the bean names of spec from the
+// config, so `name:"db-${REGION}"` injects db-eu when REGION is eu. Names are
+// expanded when the field is loaded, a later change of the config doesn't
+// rewire the field.
+func (c *Container) expandSpec(spec tagSpec) (tagSpec, error) {
+	name, err := c.expand(spec.name)
+	if err != nil {
+		return spec, err
+	}
+	spec.name = name
+	if fallback, ok := spec.option(_defaultOption); ok && strings.Contains(fallback, "${") {
+		if fallback, err = c.expand(fallback); err != nil {
+			return spec, err
+		}
+		options := make(map[string]string, len(spec.options))
+		for k, v := range spec.options {
+			options[k] = v
+		}
+		options[_defaultOption] = fallback
+		spec.options = options
+	}
+	return spec, nil
+}
+
+// expand replaces the ${KEY} variables of s by their value in the config.
+func (c *Container) expand(s string) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+	var b strings.Builder
+	for rest := s; ; {
+		start := strings.Index(rest, "${")
+		if start < 0 {
+			b.WriteString(rest)
+			return b.String(), nil
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated variable in %q", s)
+		}
+		key := rest[start+2 : start+end]
+		value, ok := "", false
+		if c.config != nil {
+			value, ok = c.config.Lookup(key)
+		}
+		if !ok {
+			return "", fmt.Errorf("failed to expand ${%s} in %q, it isn't in the config", key, s)
+		}
+		b.WriteString(rest[:start])
+		b.WriteString(value)
+		rest = rest[start+end+1:]
+	}
+}
+
+// setField parses raw into the i-th field of the struct ptr points to.
+func setField(ptr interface{}, i int, raw string) error {
+	fv, err := settable(reflect.ValueOf(ptr).Elem().Field(i))
+	if err != nil {
+		return err
+	}
+	return parseValue(fv, raw)
+}
+
+// fieldPath names the i-th field of the struct ptr points to, like "Config.Port".
+func fieldPath(ptr interface{}, i int) string {
+	typ := reflect.TypeOf(ptr).Elem()
+	return typeName(typ) + "." + typ.Field(i).Name
+}
+
+var _durationType = reflect.TypeOf(time.Duration(0))
+
+// parseValue parses raw into v according to the kind of v. Slices are
+// parsed from comma separated elements, "" being the empty slice.
+func parseValue(v reflect.Value, raw string) error {
+	if v.Type() == _durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return parseError(raw, v.Type(), err)
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return parseError(raw, v.Type(), err)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return parseError(raw, v.Type(), err)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return parseError(raw, v.Type(), err)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return parseError(raw, v.Type(), err)
+		}
+		v.SetFloat(f)
+	case reflect.Complex64, reflect.Complex128:
+		n, err := strconv.ParseComplex(raw, v.Type().Bits())
+		if err != nil {
+			return parseError(raw, v.Type(), err)
+		}
+		v.SetComplex(n)
+	case reflect.Slice:
+		var elems []string
+		if raw != "" {
+			elems = strings.Split(raw, ",")
+		}
+		s := reflect.MakeSlice(v.Type(), len(elems), len(elems))
+		for i, elem := range elems {
+			if err := parseValue(s.Index(i), strings.TrimSpace(elem)); err != nil {
+				return fmt.Errorf("element %d: %v", i, err)
+			}
+		}
+		v.Set(s)
+	default:
+		return fmt.Errorf("unsupported value type %v", v.Type())
+	}
+	return nil
+}
+
+// parseError describes why raw isn't a valid typ, without the strconv prefix.
+func parseError(raw string, typ reflect.Type, err error) error {
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		err = numErr.Err
+	}
+	return fmt.Errorf("cannot parse %q as %v: %v", raw, typ, err)
+}
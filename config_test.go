@@ -0,0 +1,160 @@
+package keeper
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type mapSource struct {
+	values   map[string]string
+	onChange func(keys []string)
+}
+
+func (s *mapSource) Lookup(key string) (string, bool) {
+	v, ok := s.values[key]
+	return v, ok
+}
+
+func (s *mapSource) Watch(onChange func(keys []string)) {
+	s.onChange = onChange
+}
+
+type httpServer struct {
+	addr     string        `value:"http.addr"`
+	timeout  time.Duration `value:"http.timeout"`
+	maxConns int           `value:"http.maxConns,optional"`
+	reloads  int
+}
+
+func (s *httpServer) OnConfigChange() {
+	s.reloads++
+}
+
+func TestContainer_Value(t *testing.T) {
+	unsafeOnly(t)
+	src := &mapSource{values: map[string]string{"http.addr": ":8080", "http.timeout": "5s"}}
+	c := New(WithConfig(src))
+	srv := new(httpServer)
+	if err := c.Register(srv, Name("httpServer")); err != nil {
+		t.Fatal(err)
+	}
+	if srv.addr != ":8080" || srv.timeout != 5*time.Second || srv.maxConns != 0 {
+		t.Fatalf("got %+v", srv)
+	}
+
+	src.values["http.addr"] = ":9090"
+	src.onChange([]string{"http.addr"})
+	if srv.addr != ":9090" || srv.reloads != 1 {
+		t.Fatalf("got %+v after reload", srv)
+	}
+	src.onChange([]string{"unrelated"})
+	if srv.reloads != 1 {
+		t.Fatal("unrelated change should not notify")
+	}
+}
+
+type guardedServer struct {
+	sync.RWMutex
+	addr string `value:"http.addr"`
+}
+
+func (s *guardedServer) Addr() string {
+	s.RLock()
+	defer s.RUnlock()
+	return s.addr
+}
+
+func TestContainer_ValueReloadLocked(t *testing.T) {
+	unsafeOnly(t)
+	src := &mapSource{values: map[string]string{"http.addr": ":8080"}}
+	c := New(WithConfig(src))
+	srv := new(guardedServer)
+	if err := c.Register(srv, Name("guardedServer")); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	go func() { // run with -race
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			srv.Addr()
+		}
+	}()
+	src.onChange(nil)
+	<-done
+	if srv.Addr() != ":8080" {
+		t.Fatalf("got %q", srv.Addr())
+	}
+}
+
+func TestContainer_ValueMissing(t *testing.T) {
+	c := New(WithConfig(&mapSource{values: map[string]string{"http.timeout": "soon"}}))
+	if err := c.Register(new(httpServer), Name("httpServer")); err == nil {
+		t.Fatal("expected error for missing and malformed values")
+	}
+}
+
+type coercedConfig struct {
+	Ratio float32    `value:"ratio"`
+	Phase complex128 `value:"phase"`
+	Hosts []string   `value:"hosts"`
+	Ports []uint16   `value:"ports"`
+	Debug bool       `value:"debug"`
+}
+
+func TestContainer_ValueCoercion(t *testing.T) {
+	src := &mapSource{values: map[string]string{
+		"ratio": "0.5", "phase": "1+2i", "hosts": "a, b,c", "ports": "80,443", "debug": "true",
+	}}
+	cfg := new(coercedConfig)
+	if err := New(WithConfig(src)).Register(cfg, Name("config")); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Ratio != 0.5 || cfg.Phase != 1+2i || !cfg.Debug ||
+		len(cfg.Hosts) != 3 || cfg.Hosts[1] != "b" || len(cfg.Ports) != 2 || cfg.Ports[1] != 443 {
+		t.Fatalf("got %+v", cfg)
+	}
+}
+
+func TestContainer_ValueCoercionError(t *testing.T) {
+	tests := map[string]string{
+		"ratio": `failed to load value ratio into coercedConfig.Ratio: cannot parse "half" as float32: invalid syntax`,
+		"ports": `failed to load value ports into coercedConfig.Ports: element 1: cannot parse "70000" as uint16: value out of range`,
+	}
+	for key, want := range tests {
+		values := map[string]string{"ratio": "1", "phase": "0", "hosts": "", "ports": "80", "debug": "false"}
+		values[key] = map[string]string{"ratio": "half", "ports": "80,70000"}[key]
+		err := New(WithConfig(&mapSource{values: values}), WithoutCallSites()).Register(new(coercedConfig), Name("config"))
+		if err == nil || err.Error() != want {
+			t.Errorf("got %v, want %s", err, want)
+		}
+	}
+}
+
+type regionalCtl struct {
+	helloSrv *HelloSrv `name:"hello-${REGION}"`
+	fallback *HelloSrv `name:"hello-${SHARD},default=hello-${REGION}"`
+}
+
+func TestContainer_ExpandName(t *testing.T) {
+	unsafeOnly(t)
+	c := New(WithConfig(&mapSource{values: map[string]string{"REGION": "eu", "SHARD": "7"}}))
+	eu := &HelloSrv{word: "hallo"}
+	c.Register(&HelloSrv{word: "hello"}, Name("hello-us"))
+	c.Register(eu, Name("hello-eu"))
+	ctl := new(regionalCtl)
+	if err := c.Register(ctl, Name("regionalCtl")); err != nil {
+		t.Fatal(err)
+	}
+	if ctl.helloSrv != eu || ctl.fallback != eu {
+		t.Fatalf("got %+v", ctl)
+	}
+
+	c = New()
+	c.Register(eu, Name("hello-eu"))
+	err := c.Register(new(regionalCtl), Name("regionalCtl"))
+	if err == nil || !strings.Contains(err.Error(), "failed to expand ${REGION}") {
+		t.Fatalf("got %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package keeper
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// WithConfigStruct is an Option that injects sections of cfg, a decoded
+// configuration like a JSON document or a protobuf message, into the fields
+// tagged with `config:"server.http"`. A path is a dot separated list of
+// struct fields, matched by their json or protobuf name or case-insensitively
+// by their Go name, and of the keys of maps; the empty path is cfg itself.
+// A pointer field to a struct section shares it with cfg when it's
+// addressable, other fields receive a copy.
+func WithConfigStruct(cfg interface{}) Option {
+	return optionFunc(func(c *Container) {
+		c.configRoot = cfg
+	})
+}
+
+// loadConfig sets the i-th field of the struct ptr points to from the
+// section of the config struct at the path of tag.
+func (c *Container) loadConfig(ptr interface{}, i int, tag string) (bool, error) {
+	spec, err := parseTag(tag)
+	if err != nil {
+		return false, err
+	}
+	section, ok := reflect.Value{}, false
+	if c.configRoot != nil {
+		if section, ok, err = configSection(reflect.ValueOf(c.configRoot), spec.name); err != nil {
+			return false, fmt.Errorf("failed to load config %s: %v", spec.name, err)
+		}
+	}
+	if !ok {
+		if spec.flag(_optionalTag) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to load config %s", spec.name)
+	}
+	fv := reflect.ValueOf(ptr).Elem().Field(i)
+	nv, err := sectionValue(fv.Type(), section)
+	if err != nil {
+		return false, fmt.Errorf("failed to load config %s into %s: %v", spec.name, fieldPath(ptr, i), err)
+	}
+	if fv, err = settable(fv); err != nil {
+		return false, fmt.Errorf("failed to load config %s into %s: %v", spec.name, fieldPath(ptr, i), err)
+	}
+	fv.Set(nv)
+	return true, nil
+}
+
+// configSection navigates v along path, and reports whether the section
+// exists. Nil pointers, interfaces and maps on the way are missing sections.
+func configSection(v reflect.Value, path string) (reflect.Value, bool, error) {
+	if path == "" {
+		return v, v.IsValid(), nil
+	}
+	for walked, seg := range strings.Split(path, ".") {
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return v, false, nil
+			}
+			v = v.Elem()
+		}
+		switch v.Kind() {
+		case reflect.Struct:
+			f, ok := sectionField(v.Type(), seg)
+			if !ok {
+				return v, false, nil
+			}
+			v = v.FieldByIndex(f.Index)
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return v, false, fmt.Errorf("%s has keys of type %v", sectionPath(path, walked), v.Type().Key())
+			}
+			v = v.MapIndex(reflect.ValueOf(seg).Convert(v.Type().Key()))
+			if !v.IsValid() {
+				return v, false, nil
+			}
+		default:
+			return v, false, fmt.Errorf("%s is a %v, it has no section %s", sectionPath(path, walked), v.Type(), seg)
+		}
+	}
+	return v, true, nil
+}
+
+// sectionPath is the path of the section of path at which walked segments
+// were navigated.
+func sectionPath(path string, walked int) string {
+	if walked == 0 {
+		return "the config"
+	}
+	return strings.Join(strings.Split(path, ".")[:walked], ".")
+}
+
+// sectionField finds the exported field of typ named name in JSON, in
+// protobuf, or in Go ignoring case.
+func sectionField(typ reflect.Type, name string) (reflect.StructField, bool) {
+	var fold *reflect.StructField
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		if jsonName := strings.Split(f.Tag.Get("json"), ",")[0]; jsonName == name {
+			return f, true
+		}
+		for _, opt := range strings.Split(f.Tag.Get("protobuf"), ",") {
+			if opt == "name="+name || opt == "json="+name {
+				return f, true
+			}
+		}
+		if fold == nil && strings.EqualFold(f.Name, name) {
+			fold = &f
+		}
+	}
+	if fold != nil {
+		return *fold, true
+	}
+	return reflect.StructField{}, false
+}
+
+// sectionValue converts section to a value of typ, taking its address for
+// a pointer field, or copying it when it isn't addressable.
+func sectionValue(typ reflect.Type, section reflect.Value) (reflect.Value, error) {
+	for section.Kind() == reflect.Interface && !section.IsNil() {
+		section = section.Elem()
+	}
+	st := section.Type()
+	switch {
+	case st.AssignableTo(typ):
+		return section, nil
+	case typ.Kind() == reflect.Ptr && st.AssignableTo(typ.Elem()):
+		if section.CanAddr() {
+			return section.Addr(), nil
+		}
+		nv := reflect.New(typ.Elem())
+		nv.Elem().Set(section)
+		return nv, nil
+	case st.Kind() == reflect.Ptr && !section.IsNil() && st.Elem().AssignableTo(typ):
+		return section.Elem(), nil
+	}
+	return reflect.Value{}, fmt.Errorf("cannot use %v as %v", st, typ)
+}
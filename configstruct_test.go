@@ -0,0 +1,80 @@
+package keeper
+
+import (
+	"strings"
+	"testing"
+)
+
+type httpConfig struct {
+	Addr string `json:"addr"`
+	TLS  bool   `json:"tls"`
+}
+
+type appConfig struct {
+	Server struct {
+		HTTP httpConfig `json:"http"`
+	} `json:"server"`
+	Limits    map[string]int    `json:"limits"`
+	Upstreams map[string]string `protobuf:"bytes,3,rep,name=upstreams,json=upstreams"`
+	LogLevel  string
+}
+
+type configuredServer struct {
+	config    *httpConfig `config:"server.http"`
+	addr      string      `config:"server.http.addr"`
+	burst     int         `config:"limits.burst"`
+	upstream  string      `config:"upstreams.billing"`
+	level     string      `config:"loglevel"`
+	fallback  int         `config:"limits.missing,optional"`
+	entireApp appConfig   `config:""`
+}
+
+func TestConfigStruct(t *testing.T) {
+	unsafeOnly(t)
+	cfg := &appConfig{
+		Limits:    map[string]int{"burst": 10},
+		Upstreams: map[string]string{"billing": "billing:443"},
+		LogLevel:  "debug",
+	}
+	cfg.Server.HTTP = httpConfig{Addr: ":8080", TLS: true}
+	c := New(WithConfigStruct(cfg))
+	s := new(configuredServer)
+	if err := c.Register(s, Name("configuredServer")); err != nil {
+		t.Fatal(err)
+	}
+	if s.config != &cfg.Server.HTTP {
+		t.Error("section should be shared with the config")
+	}
+	if s.addr != ":8080" || s.burst != 10 || s.upstream != "billing:443" || s.level != "debug" {
+		t.Errorf("got %+v", s)
+	}
+	if s.fallback != 0 || s.entireApp.LogLevel != "debug" {
+		t.Errorf("got %+v", s)
+	}
+}
+
+func TestConfigStructErrors(t *testing.T) {
+	type missing struct {
+		port int `config:"server.port"`
+	}
+	type mistyped struct {
+		port int `config:"server.http.addr"`
+	}
+	type tooDeep struct {
+		port int `config:"loglevel.x"`
+	}
+	for _, tt := range []struct {
+		bean interface{}
+		want string
+	}{
+		{new(missing), "failed to load config server.port"},
+		{new(mistyped), "cannot use string as int"},
+		{new(tooDeep), "loglevel is a string, it has no section x"},
+	} {
+		c := New(WithConfigStruct(appConfig{}))
+		err := c.Register(tt.bean, Name("bean"))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("got %v, want %q", err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package keeper
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+var _errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// ProvideConstructor registers ctor, a constructor in the style of dig and
+// fx like func(*DB, Config) (*Repo, error), as the typed factory of each of
+// its results but the error. ctor is called once, when one of its results
+// is first resolved by ResolveType, with its parameters resolved by
+// ResolveType too. It lets the providers of another framework be installed
+// unchanged while migrating.
+func (c *Container) ProvideConstructor(ctor interface{}) error {
+	fv := reflect.ValueOf(ctor)
+	if fv.Kind() != reflect.Func || fv.IsNil() {
+		return fmt.Errorf("cannot provide %T, it isn't a func", ctor)
+	}
+	ft := fv.Type()
+	if ft.IsVariadic() {
+		return fmt.Errorf("cannot provide %v, it's variadic", ft)
+	}
+	var results []int
+	for i := 0; i < ft.NumOut(); i++ {
+		if ft.Out(i) == _errorType {
+			if i != ft.NumOut()-1 {
+				return fmt.Errorf("cannot provide %v, the error must be the last result", ft)
+			}
+			continue
+		}
+		results = append(results, i)
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("cannot provide %v, it has no result", ft)
+	}
+	call := &lazyBean{factory: func(k Keeper) (interface{}, error) {
+		in := make([]reflect.Value, ft.NumIn())
+		for i := range in {
+			dep, err := k.ResolveType(ft.In(i))
+			if err != nil {
+				return nil, fmt.Errorf("parameter %d of %v: %w", i, ft, err)
+			}
+			in[i] = reflect.ValueOf(dep)
+		}
+		out := fv.Call(in)
+		if last := out[len(out)-1]; last.Type() == _errorType && !last.IsNil() {
+			return nil, last.Interface().(error)
+		}
+		return out, nil
+	}}
+	for _, i := range results {
+		i := i
+		err := c.RegisterTyped(ft.Out(i), func(k Keeper) (interface{}, error) {
+			out, err := call.get(c, scopeOf(k))
+			if err != nil {
+				return nil, err
+			}
+			return out.([]reflect.Value)[i].Interface(), nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Constructors returns a constructor func() T for each bean of k, T being
+// the type of the bean, to provide the beans of k to dig or fx while
+// migrating from keeper. Beans of the same type are ambiguous to those
+// frameworks, they are reported as an error.
+func Constructors(k Keeper) ([]interface{}, error) {
+	var ctors []interface{}
+	owners := make(map[reflect.Type]string)
+	for _, info := range k.Beans() {
+		bean := k.Find(info.Name)
+		if bean == nil {
+			continue
+		}
+		typ := reflect.TypeOf(bean)
+		if owner, ok := owners[typ]; ok {
+			names := []string{owner, info.Name}
+			sort.Strings(names)
+			return nil, fmt.Errorf("cannot construct %v, it's the type of %s and %s", typ, names[0], names[1])
+		}
+		owners[typ] = info.Name
+		v := reflect.ValueOf(bean)
+		ctor := reflect.MakeFunc(reflect.FuncOf(nil, []reflect.Type{typ}, false), func([]reflect.Value) []reflect.Value {
+			return []reflect.Value{v}
+		})
+		ctors = append(ctors, ctor.Interface())
+	}
+	return ctors, nil
+}
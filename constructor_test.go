@@ -0,0 +1,75 @@
+package keeper
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type digConfig struct {
+	dsn string
+}
+
+type digRepo struct {
+	config *digConfig
+}
+
+func TestContainer_ProvideConstructor(t *testing.T) {
+	c := New()
+	calls := 0
+	err := c.ProvideConstructor(func() (*digConfig, *HelloSrv) {
+		calls++
+		return &digConfig{dsn: "postgres://"}, &HelloSrv{word: "dig"}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ProvideConstructor(func(config *digConfig) (*digRepo, error) {
+		return &digRepo{config: config}, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := c.ResolveType(reflect.TypeOf(new(digRepo)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.(*digRepo).config.dsn != "postgres://" {
+		t.Fatalf("got %+v", repo)
+	}
+	srv, err := c.ResolveType(reflect.TypeOf(new(HelloSrv)))
+	if err != nil || srv.(*HelloSrv).word != "dig" || calls != 1 {
+		t.Fatalf("got %v, %v after %d calls", srv, err, calls)
+	}
+}
+
+func TestContainer_ProvideConstructorError(t *testing.T) {
+	c := New()
+	c.ProvideConstructor(func() (*digConfig, error) {
+		return nil, errors.New("no DSN")
+	})
+	if _, err := c.ResolveType(reflect.TypeOf(new(digConfig))); err == nil {
+		t.Fatal("expected the constructor error")
+	}
+	for _, ctor := range []interface{}{func() {}, func() (error, *digConfig) { return nil, nil }, 42} {
+		if err := c.ProvideConstructor(ctor); err == nil {
+			t.Errorf("expected %T to be refused", ctor)
+		}
+	}
+}
+
+func TestConstructors(t *testing.T) {
+	c := New()
+	srv := &HelloSrv{}
+	c.Register(srv, Name("helloService"))
+	ctors, err := Constructors(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ctors) != 1 || ctors[0].(func() *HelloSrv)() != srv {
+		t.Fatalf("got %v", ctors)
+	}
+	c.Register(&HelloSrv{}, Name("otherService"))
+	if _, err := Constructors(c); err == nil {
+		t.Fatal("expected beans of the same type to be refused")
+	}
+}
@@ -0,0 +1,54 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// ContextKey is the key of the context values injected into fields tagged
+// with `ctx:"name"`.
+type ContextKey string
+
+// WithValue returns a copy of ctx carrying val for fields tagged `ctx:"name"`.
+func WithValue(ctx context.Context, name string, val interface{}) context.Context {
+	return context.WithValue(ctx, ContextKey(name), val)
+}
+
+// ProvideContext is Provider for request scoped beans: besides the beans of
+// the container, fields tagged with `ctx:"requestID"` get the value stored in
+// ctx by WithValue(ctx, "requestID", id).
+//
+//	type handler struct {
+//		repo      *Repo  `name:"repo"`
+//		requestID string `ctx:"requestID"`
+//	}
+func (c *Container) ProvideContext(ctx context.Context, ptr interface{}) error {
+	_, err := c.load(ctx, ptr, noopRegisterOption)
+	return err
+}
+
+// loadContext sets the i-th field of the struct ptr points to from ctx.
+func loadContext(ctx context.Context, ptr interface{}, i int, tag string) (bool, error) {
+	spec, err := parseTag(tag)
+	if err != nil {
+		return false, err
+	}
+	val := ctx.Value(ContextKey(spec.name))
+	if val == nil {
+		if spec.flag(_optionalTag) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to load context value %s", spec.name)
+	}
+	fv := reflect.ValueOf(ptr).Elem().Field(i)
+	nv, err := assignValue(fv.Type(), val, false)
+	if err != nil {
+		return false, fmt.Errorf("failed to load context value %s: %v", spec.name, err)
+	}
+	if fv, err = settable(fv); err != nil {
+		return false, fmt.Errorf("failed to load context value %s: %v", spec.name, err)
+	}
+	fv.Set(nv)
+	return true, nil
+}
@@ -0,0 +1,32 @@
+package keeper
+
+import (
+	"context"
+	"testing"
+)
+
+type requestHandler struct {
+	helloSrv  *HelloSrv `name:"helloService"`
+	requestID string    `ctx:"requestID"`
+	userID    int64     `ctx:"userID,optional"`
+}
+
+func TestContainer_ProvideContext(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	c.Register(new(HelloSrv), Name("helloService"))
+	ctx := WithValue(context.Background(), "requestID", "req-1")
+	h := new(requestHandler)
+	if err := c.ProvideContext(ctx, h); err != nil {
+		t.Fatal(err)
+	}
+	if h.helloSrv == nil || h.requestID != "req-1" || h.userID != 0 {
+		t.Fatalf("got %+v", h)
+	}
+	if err := c.ProvideContext(context.Background(), new(requestHandler)); err == nil {
+		t.Fatal("expected error for missing request id")
+	}
+	if err := c.ProvideContext(WithValue(context.Background(), "requestID", 42), new(requestHandler)); err == nil {
+		t.Fatal("expected error for mistyped request id")
+	}
+}
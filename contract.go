@@ -0,0 +1,34 @@
+package keeper
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// AssertTypes checks each bean of expected is registered with the expected
+// type, or a type assignable to it, and returns all the mismatches
+// together. It's a cheap contract between teams sharing a container, checked
+// at startup; keepergen -mode types generates the map from a manifest.
+// Factory beans are built to be checked.
+func (c *Container) AssertTypes(expected map[string]reflect.Type) error {
+	names := make([]string, 0, len(expected))
+	for name := range expected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var errs multiError
+	for _, name := range names {
+		want := expected[name]
+		bean := c.lookup(name)
+		switch {
+		case bean == nil:
+			errs = append(errs, fmt.Errorf("bean %s is missing, want %v", name, want))
+		case want == nil:
+			errs = append(errs, fmt.Errorf("bean %s has no expected type", name))
+		case !reflect.TypeOf(bean).AssignableTo(want):
+			errs = append(errs, fmt.Errorf("bean %s is %T, want %v", name, bean, want))
+		}
+	}
+	return errs.errOrNil()
+}
@@ -0,0 +1,28 @@
+package keeper
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestContainer_AssertTypes(t *testing.T) {
+	c := New()
+	c.Register(&HelloSrv{}, Name("helloService"))
+	c.Register(noopCache{}, Name("cache"))
+	err := c.AssertTypes(map[string]reflect.Type{
+		"helloService": reflect.TypeOf(new(HelloSrv)),
+		"cache":        reflect.TypeOf((*Cache)(nil)).Elem(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.AssertTypes(map[string]reflect.Type{
+		"helloService": reflect.TypeOf(new(HelloCtl)),
+		"mailer":       reflect.TypeOf((*Mailer)(nil)).Elem(),
+	})
+	want := "bean helloService is *keeper.HelloSrv, want *keeper.HelloCtl; bean mailer is missing, want keeper.Mailer"
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("got %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+package keeper
+
+import "reflect"
+
+// InjectByFieldName is an Option that injects the untagged fields named
+// after a registered bean, when the bean fits the field: helloSrv gets the
+// bean "helloSrv". Other untagged fields are left alone.
+//
+//	type HelloCtl struct {
+//		helloSrv *HelloSrv
+//	}
+func InjectByFieldName() Option {
+	return optionFunc(func(c *Container) {
+		c.fieldNames = true
+	})
+}
+
+// byFieldName reports whether the untagged field is injected by its name.
+func (c *Container) byFieldName(field reflect.StructField) bool {
+	if !c.fieldNames {
+		return false
+	}
+	bean := c.resolve(field.Name)
+	if bean == nil {
+		return false
+	}
+	_, err := c.assign(field.Type, bean)
+	return err == nil
+}
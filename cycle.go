@@ -0,0 +1,103 @@
+package keeper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Beans can't depend on each other by plain fields, as a bean must be
+// registered before it's injected, but cycles may be broken two ways:
+//
+// A field naming the bean being registered, optional or not, gets the bean
+// itself, like the next stage of a recursive processor:
+//
+//	type TreeWalker struct {
+//		child *TreeWalker `name:"walker"`
+//	}
+//
+// A Lazy field looks up its bean when it's used rather than when the bean
+// holding it is registered, so it may name a bean registered later, like a
+// middleware reaching back to the chain which embeds it.
+
+// loadSelf sets the i-th field of the struct ptr points to, which names the
+// bean being registered, to the bean itself. It's no dependency.
+func (c *Container) loadSelf(ptr interface{}, i int, name string) error {
+	fv := reflect.ValueOf(ptr).Elem().Field(i)
+	nv, err := c.assign(fv.Type(), ptr)
+	if err != nil {
+		return fmt.Errorf("failed to load %s into itself, %s: %v", name, fieldPath(ptr, i), err)
+	}
+	if fv, err = settable(fv); err != nil {
+		return fmt.Errorf("failed to load %s into itself, %s: %v", name, fieldPath(ptr, i), err)
+	}
+	fv.Set(nv)
+	return nil
+}
+
+// Lazy is a field looking up the bean named by its `name` tag, or by the name
+// of the field, when Get is called, which breaks dependency cycles. The bean
+// is looked up on each call, so a swapped bean is seen at once.
+//
+//	type Middleware struct {
+//		chain keeper.Lazy[*Chain] `name:"chain"`
+//	}
+type Lazy[T any] struct {
+	get func() (interface{}, error)
+}
+
+// Get returns the bean, an ErrNotFound error if it isn't registered yet.
+func (l Lazy[T]) Get() (T, error) {
+	var zero T
+	if l.get == nil {
+		return zero, fmt.Errorf("lazy %v isn't injected", reflect.TypeOf((*T)(nil)).Elem())
+	}
+	bean, err := l.get()
+	if err != nil {
+		return zero, err
+	}
+	return bean.(T), nil
+}
+
+func (Lazy[T]) lazyOf(c *Container, name string, options registerOptions) interface{} {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	return Lazy[T]{get: func() (interface{}, error) {
+		bean := c.resolve(name)
+		if bean == nil {
+			return nil, fmt.Errorf("failed to load %s: %w", name, ErrNotFound)
+		}
+		if err := c.checkCapability(name, bean, options); err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", name, err)
+		}
+		v, err := c.assign(typ, bean)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %v", name, err)
+		}
+		return v.Interface(), nil
+	}}
+}
+
+// lazyField is implemented by the Lazy types.
+type lazyField interface {
+	lazyOf(c *Container, name string, options registerOptions) interface{}
+}
+
+var _lazyFieldType = reflect.TypeOf((*lazyField)(nil)).Elem()
+
+// setLazy sets the i-th field of the struct ptr points to, a Lazy, to look
+// up the bean tag names with the capabilities of the bean of options.
+func (c *Container) setLazy(ptr interface{}, i int, tag string, options registerOptions) error {
+	spec, err := parseTag(tag)
+	if err != nil {
+		return fmt.Errorf("failed to set lazy %s: %v", fieldPath(ptr, i), err)
+	}
+	if spec, err = c.expandSpec(spec); err != nil {
+		return fmt.Errorf("failed to set lazy %s: %v", fieldPath(ptr, i), err)
+	}
+	fv := reflect.ValueOf(ptr).Elem().Field(i)
+	lazy := reflect.Zero(fv.Type()).Interface().(lazyField).lazyOf(c, spec.name, options)
+	if fv, err = settable(fv); err != nil {
+		return fmt.Errorf("failed to set lazy %s: %v", fieldPath(ptr, i), err)
+	}
+	fv.Set(reflect.ValueOf(lazy))
+	return nil
+}
@@ -0,0 +1,68 @@
+package keeper
+
+import (
+	"errors"
+	"testing"
+)
+
+type treeWalker struct {
+	child *treeWalker `name:"walker"`
+}
+
+type chainLink interface {
+	Handle() string
+}
+
+type middleware struct {
+	chain Lazy[chainLink] `name:"chain"`
+}
+
+type chain struct {
+	*middleware `name:"middleware"`
+}
+
+func (*chain) Handle() string { return "handled" }
+
+func TestSelfReference(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	w := new(treeWalker)
+	if err := c.Register(w, Name("walker")); err != nil {
+		t.Fatal(err)
+	}
+	if w.child != w {
+		t.Error("walker should get itself")
+	}
+	if deps := c.Beans()[0].Dependencies; len(deps) != 0 {
+		t.Errorf("got dependencies %v, itself isn't one", deps)
+	}
+}
+
+func TestLazy(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	m := new(middleware)
+	if err := c.Register(m, Name("middleware")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.chain.Get(); !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v before the chain is registered", err)
+	}
+	if err := c.Register(new(chain), Name("chain")); err != nil {
+		t.Fatal(err)
+	}
+	link, err := m.chain.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if link.Handle() != "handled" || link.(*chain).middleware != m {
+		t.Error("the middleware should reach the chain embedding it")
+	}
+}
+
+func TestLazyNotInjected(t *testing.T) {
+	var l Lazy[*HelloSrv]
+	if _, err := l.Get(); err == nil {
+		t.Error("a lazy which isn't injected should fail")
+	}
+}
@@ -0,0 +1,123 @@
+package keeper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// BeanInfo describes a registered bean.
+type BeanInfo struct {
+	Name         string            `json:"name"`
+	Type         string            `json:"type"`
+	Description  string            `json:"description,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	Dependencies []string          `json:"dependencies,omitempty"`
+	Adopted      bool              `json:"adopted,omitempty"` // registered by Adopt
+	Lazy         bool              `json:"lazy,omitempty"`    // built by a factory, Type is empty until built
+	Build        *BuildStats       `json:"build,omitempty"`   // set by AccountResources
+	Site         string            `json:"site,omitempty"`    // file:line of the registration
+	Fingerprint  string            `json:"fingerprint"`       // hash of the wiring, see Container.Fingerprint
+}
+
+// Beans describes the registered beans in the Ordering of the container.
+func (c *Container) Beans() []BeanInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	nodes := c.published()
+	infos := make([]BeanInfo, 0, len(c.order))
+	for _, name := range c.enumeration() {
+		bean := nodes[name]
+		lazy, isLazy := bean.(*lazyBean)
+		if isLazy {
+			bean = lazy.peek()
+		}
+		var typ string
+		if bean != nil {
+			typ = reflect.TypeOf(bean).String()
+		}
+		infos = append(infos, BeanInfo{
+			Name:         name,
+			Type:         typ,
+			Lazy:         isLazy,
+			Description:  c.opts[name].Description,
+			Labels:       copyLabels(c.opts[name].Labels),
+			Dependencies: append([]string(nil), c.deps[name]...),
+			Adopted:      c.opts[name].adopted,
+			Build:        c.buildStats(name),
+			Site:         c.opts[name].site,
+			Fingerprint:  beanFingerprint(name, typ, isLazy, c.opts[name], c.deps[name]),
+		})
+	}
+	return infos
+}
+
+func (c *Container) buildStats(name string) *BuildStats {
+	s, ok := c.stats[name]
+	if !ok {
+		return nil
+	}
+	return &s
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	if labels == nil {
+		return nil
+	}
+	cp := make(map[string]string, len(labels))
+	for k, v := range labels {
+		cp[k] = v
+	}
+	return cp
+}
+
+// WriteDOT writes the dependency graph of k in the Graphviz DOT language,
+// each edge points from a bean to one of its dependencies. Adopted beans are dashed.
+func WriteDOT(w io.Writer, k Keeper) error {
+	var b strings.Builder
+	b.WriteString("digraph keeper {\n")
+	for _, info := range k.Beans() {
+		label := []string{info.Name, info.Type}
+		if info.Description != "" {
+			label = append(label, info.Description)
+		}
+		style := ""
+		if info.Adopted {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(&b, "\t%q [label=%s%s];\n", info.Name, quoteDOT(label...), style)
+		for _, dep := range info.Dependencies {
+			fmt.Fprintf(&b, "\t%q -> %q;\n", info.Name, dep)
+		}
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// quoteDOT quotes lines as a DOT string, separated by \n line breaks.
+func quoteDOT(lines ...string) string {
+	escaped := make([]string, len(lines))
+	for i, line := range lines {
+		escaped[i] = strings.ReplaceAll(strings.ReplaceAll(line, `\`, `\\`), `"`, `\"`)
+	}
+	return `"` + strings.Join(escaped, `\n`) + `"`
+}
+
+// DebugHandler serves the beans of k as JSON, or as DOT with ?format=dot.
+func DebugHandler(k Keeper) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("format") == "dot" {
+			w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+			WriteDOT(w, k)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(k.Beans())
+	})
+}
@@ -0,0 +1,101 @@
+package keeper
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newDebugContainer(t *testing.T) Keeper {
+	c := New()
+	if err := c.Register(new(HelloSrv), Name("helloService"), Description("says hello")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Register(new(HelloCtl), Name("helloCtl")); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestContainer_Beans(t *testing.T) {
+	unsafeOnly(t)
+	infos := newDebugContainer(t).Beans()
+	if len(infos) != 2 {
+		t.Fatalf("got %d beans", len(infos))
+	}
+	if infos[0].Name != "helloService" || infos[0].Type != "*keeper.HelloSrv" || infos[0].Description != "says hello" {
+		t.Fatalf("got %+v", infos[0])
+	}
+	if len(infos[1].Dependencies) != 1 || infos[1].Dependencies[0] != "helloService" {
+		t.Fatalf("got %+v", infos[1])
+	}
+}
+
+func TestDebugHandler(t *testing.T) {
+	unsafeOnly(t)
+	h := DebugHandler(newDebugContainer(t))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/keeper", nil))
+	var infos []BeanInfo
+	if err := json.NewDecoder(rec.Body).Decode(&infos); err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 2 {
+		t.Fatalf("got %+v", infos)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/keeper?format=dot", nil))
+	dot := rec.Body.String()
+	for _, want := range []string{`"helloCtl" -> "helloService";`, `says hello`} {
+		if !strings.Contains(dot, want) {
+			t.Fatalf("missing %q in\n%s", want, dot)
+		}
+	}
+}
+
+func TestWriteDOTEscape(t *testing.T) {
+	c := New()
+	c.Register(&HelloSrv{}, Name("helloService"), Description(`greets in C:\"hello"`))
+	var b strings.Builder
+	if err := WriteDOT(&b, c); err != nil {
+		t.Fatal(err)
+	}
+	want := `[label="helloService\n*keeper.HelloSrv\ngreets in C:\\\"hello\""];`
+	if !strings.Contains(b.String(), want) {
+		t.Fatalf("missing %s in\n%s", want, b.String())
+	}
+}
+
+var legacyHello = &HelloSrv{word: "legacy"}
+
+func TestContainer_Adopt(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	if err := c.Adopt("helloService", legacyHello); err != nil {
+		t.Fatal(err)
+	}
+	ctl := new(HelloCtl)
+	if err := c.Register(ctl, Name("helloCtl")); err != nil {
+		t.Fatal(err)
+	}
+	if ctl.helloSrv.word != "legacy" {
+		t.Fatal("adopted bean should be injected into dependents")
+	}
+	infos := c.Beans()
+	if !infos[0].Adopted || infos[1].Adopted {
+		t.Fatalf("got %+v", infos)
+	}
+	// an adopted bean is never loaded, even with unresolvable tags
+	if err := c.Adopt("orphanCtl", new(HelloCtl)); err != nil {
+		t.Fatal(err)
+	}
+	var dot strings.Builder
+	WriteDOT(&dot, c)
+	if !strings.Contains(dot.String(), "style=dashed") {
+		t.Fatalf("adopted beans should be dashed:\n%s", dot.String())
+	}
+}
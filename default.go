@@ -0,0 +1,38 @@
+package keeper
+
+import "sync"
+
+var (
+	defaultMu     sync.Mutex
+	defaultKeeper Keeper
+)
+
+// Default returns the package level container, which is created on first use.
+// It suits small programs and registration from init functions; larger
+// applications should create their own container with New and pass it around.
+func Default() Keeper {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultKeeper == nil {
+		defaultKeeper = New()
+	}
+	return defaultKeeper
+}
+
+// RegisterDefault registers ptr into the Default container.
+func RegisterDefault(ptr interface{}, opts ...RegisterOption) error {
+	return Default().Register(ptr, opts...)
+}
+
+// FindDefault finds the bean of the name in the Default container.
+func FindDefault(name string) interface{} {
+	return Default().Find(name)
+}
+
+// ResetDefault drops the Default container, the next use creates an empty one.
+// It's meant for tests which register into the Default container.
+func ResetDefault() {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultKeeper = nil
+}
@@ -0,0 +1,40 @@
+package keeper
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestRegisterDefault(t *testing.T) {
+	defer ResetDefault()
+	if err := RegisterDefault(new(HelloSrv), Name("helloService")); err != nil {
+		t.Fatal(err)
+	}
+	if FindDefault("helloService") == nil {
+		t.Fatal("helloService is not registered")
+	}
+	ResetDefault()
+	if FindDefault("helloService") != nil {
+		t.Fatal("ResetDefault should drop the beans")
+	}
+}
+
+func TestRegisterDefaultConcurrently(t *testing.T) {
+	defer ResetDefault()
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := RegisterDefault(new(HelloSrv), Name(fmt.Sprintf("helloService%d", i))); err != nil {
+				t.Error(err)
+			}
+			FindDefault("helloService0")
+		}(i)
+	}
+	wg.Wait()
+	if n := len(Default().All()); n != 16 {
+		t.Fatalf("got %d beans, want 16", n)
+	}
+}
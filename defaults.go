@@ -0,0 +1,53 @@
+package keeper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// RegisterDefaultFor registers impl as the fallback of the interface iface
+// points to: a field of that interface whose named bean isn't registered gets
+// impl injected, and so does ResolveType without a candidate. Libraries can
+// ship noop defaults which applications override by registering the bean.
+//
+//	c.RegisterDefaultFor((*Cache)(nil), noopCache{})
+func (c *Container) RegisterDefaultFor(iface interface{}, impl interface{}) error {
+	typ := reflect.TypeOf(iface)
+	if typ == nil || typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Interface {
+		return fmt.Errorf("must provide pointer to interface, got %v", typ)
+	}
+	typ = typ.Elem()
+	if impl == nil || !reflect.TypeOf(impl).Implements(typ) {
+		return fmt.Errorf("%T doesn't implement %v", impl, typ)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exist := c.defaults[typ]; exist {
+		return fmt.Errorf("register duplicate! default of %v already registered", typ)
+	}
+	c.defaults[typ] = impl
+	return nil
+}
+
+// defaultFor returns the fallback implementation of the interface typ.
+func (c *Container) defaultFor(typ reflect.Type) interface{} {
+	if typ.Kind() != reflect.Interface {
+		return nil
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if def, ok := c.defaults[typ]; ok {
+		return def
+	}
+	return c.nilObjects[typ]
+}
+
+// setDefault sets impl into the i-th field of the struct ptr points to.
+func setDefault(ptr interface{}, i int, impl interface{}) error {
+	fv, err := settable(reflect.ValueOf(ptr).Elem().Field(i))
+	if err != nil {
+		return err
+	}
+	fv.Set(reflect.ValueOf(impl))
+	return nil
+}
@@ -0,0 +1,56 @@
+package keeper
+
+import (
+	"reflect"
+	"testing"
+)
+
+type Cache interface {
+	Get(key string) (string, bool)
+}
+
+type noopCache struct{}
+
+func (noopCache) Get(string) (string, bool) { return "", false }
+
+type mapCache map[string]string
+
+func (m mapCache) Get(key string) (string, bool) {
+	v, ok := m[key]
+	return v, ok
+}
+
+type cachedRepo struct {
+	cache Cache `name:"cache"`
+}
+
+func TestContainer_RegisterDefaultFor(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	if err := c.RegisterDefaultFor((*Cache)(nil), noopCache{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.RegisterDefaultFor((*Cache)(nil), noopCache{}); err == nil {
+		t.Fatal("expected duplicate error")
+	}
+	if err := c.RegisterDefaultFor((*Cache)(nil), new(HelloSrv)); err == nil {
+		t.Fatal("expected error for a type not implementing Cache")
+	}
+	repo := new(cachedRepo)
+	if err := c.Register(repo, Name("repo")); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := repo.cache.(noopCache); !ok {
+		t.Fatalf("got %T, want noopCache", repo.cache)
+	}
+	if v, err := c.ResolveType(reflect.TypeOf((*Cache)(nil)).Elem()); err != nil || v != (noopCache{}) {
+		t.Fatalf("got %v, %v", v, err)
+	}
+
+	c.Register(mapCache{"k": "v"}, Name("cache"))
+	overridden := new(cachedRepo)
+	c.Register(overridden, Name("overridden"))
+	if _, ok := overridden.cache.(mapCache); !ok {
+		t.Fatalf("got %T, want the registered mapCache", overridden.cache)
+	}
+}
@@ -0,0 +1,48 @@
+package keeper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// DevMode is an Option for development which panics, with a hint to fix the
+// code, on misuses that are otherwise silent:
+//   - a pointer bean copied into a struct field, which won't see its changes,
+//   - Find of a name which isn't registered,
+//   - a write into an unexported field of a type outside the main module.
+//
+// Production builds should leave it off.
+func DevMode() Option {
+	return optionFunc(func(c *Container) {
+		c.dev = true
+	})
+}
+
+func (c *Container) devPanic(problem, hint string) {
+	msg := "keeper: " + problem
+	if hint != "" {
+		msg += ", " + hint
+	}
+	panic(msg)
+}
+
+// suggest hints at the registered name closest to name.
+func (c *Container) suggest(name string) string {
+	if closest := c.closestName(name); closest != "" {
+		return fmt.Sprintf("did you mean %q?", closest)
+	}
+	return "register it first"
+}
+
+// checkDev panics when injecting bean into the field of typ is a misuse.
+func (c *Container) checkDev(typ reflect.Type, field reflect.StructField, bean interface{}) {
+	bt := reflect.TypeOf(bean)
+	if bt.Kind() == reflect.Ptr && field.Type == bt.Elem() {
+		c.devPanic(fmt.Sprintf("%s.%s holds a copy of the %v bean", typeName(typ), field.Name, bt),
+			fmt.Sprintf("declare the field as %v", bt))
+	}
+	if field.PkgPath != "" && foreign(typ.PkgPath()) {
+		c.devPanic(fmt.Sprintf("%s.%s is an unexported field of package %s", typeName(typ), field.Name, typ.PkgPath()),
+			"inject into a type of your module wrapping it, or export the field")
+	}
+}
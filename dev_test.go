@@ -0,0 +1,48 @@
+package keeper
+
+import (
+	"strings"
+	"testing"
+)
+
+func expectDevPanic(t *testing.T, want string, fn func()) {
+	t.Helper()
+	defer func() {
+		p := recover()
+		if msg, _ := p.(string); !strings.Contains(msg, want) {
+			t.Fatalf("got panic %v, want %q", p, want)
+		}
+	}()
+	fn()
+}
+
+func TestContainer_DevModeCopy(t *testing.T) {
+	unsafeOnly(t)
+	c := New(DevMode())
+	c.Register(&HelloSrv{}, Name("helloService"))
+	expectDevPanic(t, "declare the field as *keeper.HelloSrv", func() {
+		c.Register(new(HelloCtl), Name("helloCtl"))
+	})
+}
+
+func TestContainer_DevModeFind(t *testing.T) {
+	c := New(DevMode())
+	c.Register(&HelloSrv{}, Name("helloService"))
+	expectDevPanic(t, `did you mean "helloService"?`, func() {
+		c.Find("helloServce")
+	})
+	if err := c.Register(new(optionalIntegration), Name("optional")); err != nil {
+		t.Fatal("missing optional dependencies should not panic")
+	}
+}
+
+func TestContainer_DevModeForeign(t *testing.T) {
+	unsafeOnly(t)
+	defer func(m string) { _mainModule = m }(_mainModule)
+	_mainModule = "example.com/app"
+	c := New(DevMode())
+	c.Register(&HelloSrv{}, Name("metrics"))
+	expectDevPanic(t, "unexported field of package github.com/tooky0630/keeper", func() {
+		c.Register(new(optionalIntegration), Name("optional"))
+	})
+}
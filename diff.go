@@ -0,0 +1,97 @@
+package keeper
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DiffReport lists how the beans of one container differ from another.
+type DiffReport struct {
+	Added   []BeanInfo   `json:"added,omitempty"`
+	Removed []BeanInfo   `json:"removed,omitempty"`
+	Changed []BeanChange `json:"changed,omitempty"`
+}
+
+// BeanChange is a bean registered in both containers with a different type or labels.
+type BeanChange struct {
+	Name   string   `json:"name"`
+	Before BeanInfo `json:"before"`
+	After  BeanInfo `json:"after"`
+}
+
+// Empty reports whether both containers hold the same beans.
+func (r DiffReport) Empty() bool {
+	return len(r.Added) == 0 && len(r.Removed) == 0 && len(r.Changed) == 0
+}
+
+func (r DiffReport) String() string {
+	var b strings.Builder
+	for _, info := range r.Added {
+		fmt.Fprintf(&b, "+ %s %s%s\n", info.Name, info.Type, formatLabels(info.Labels))
+	}
+	for _, info := range r.Removed {
+		fmt.Fprintf(&b, "- %s %s%s\n", info.Name, info.Type, formatLabels(info.Labels))
+	}
+	for _, ch := range r.Changed {
+		fmt.Fprintf(&b, "~ %s %s%s -> %s%s\n", ch.Name,
+			ch.Before.Type, formatLabels(ch.Before.Labels), ch.After.Type, formatLabels(ch.After.Labels))
+	}
+	return b.String()
+}
+
+// Diff compares the beans of a and b by name, type and labels; Added are the
+// beans only b has, Removed the ones only a has. Beans are reported in the
+// registration order of their container.
+func Diff(a, b Keeper) DiffReport {
+	var r DiffReport
+	before := indexBeans(a.Beans())
+	after := b.Beans()
+	seen := make(map[string]bool, len(after))
+	for _, info := range after {
+		seen[info.Name] = true
+		old, ok := before[info.Name]
+		switch {
+		case !ok:
+			r.Added = append(r.Added, info)
+		case old.Type != info.Type || !reflect.DeepEqual(normalizeLabels(old.Labels), normalizeLabels(info.Labels)):
+			r.Changed = append(r.Changed, BeanChange{Name: info.Name, Before: old, After: info})
+		}
+	}
+	for _, info := range a.Beans() {
+		if !seen[info.Name] {
+			r.Removed = append(r.Removed, info)
+		}
+	}
+	return r
+}
+
+func indexBeans(infos []BeanInfo) map[string]BeanInfo {
+	m := make(map[string]BeanInfo, len(infos))
+	for _, info := range infos {
+		m[info.Name] = info
+	}
+	return m
+}
+
+// normalizeLabels treats no labels and empty labels alike.
+func normalizeLabels(labels map[string]string) map[string]string {
+	if len(labels) == 0 {
+		return nil
+	}
+	return labels
+}
+
+// formatLabels formats labels as " {k=v,...}" in order of keys.
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return " {" + strings.Join(pairs, ",") + "}"
+}
@@ -0,0 +1,34 @@
+package keeper
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	stable := New()
+	stable.Register(new(HelloSrv), Name("helloService"), Label("tier", "stable"))
+	stable.Register(new(HelloCtl), Name("helloCtl"))
+	stable.Register(new(HelloSrv), Name("legacyService"))
+
+	canary := New()
+	canary.Register(new(HelloSrv), Name("helloService"), Label("tier", "canary"))
+	canary.Register(new(HelloCtl), Name("helloCtl"))
+	canary.Register(new(initializedSrv), Name("newService"))
+
+	r := Diff(stable, canary)
+	if r.Empty() {
+		t.Fatal("expected differences")
+	}
+	want := strings.Join([]string{
+		"+ newService *keeper.initializedSrv",
+		"- legacyService *keeper.HelloSrv",
+		"~ helloService *keeper.HelloSrv {tier=stable} -> *keeper.HelloSrv {tier=canary}",
+	}, "\n") + "\n"
+	if got := r.String(); got != want {
+		t.Fatalf("got\n%s", got)
+	}
+	if !Diff(stable, stable).Empty() {
+		t.Fatal("a container should not differ from itself")
+	}
+}
@@ -0,0 +1,186 @@
+package keeper
+
+import (
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"strings"
+)
+
+// FindingKind categorizes the problems reported by Doctor.
+type FindingKind string
+
+const (
+	// FindingUnresolved is a bean no other bean got injected, or a factory
+	// bean which was never built. It's fine for beans looked up with Find.
+	FindingUnresolved FindingKind = "unresolved"
+	// FindingCopied is a bean copied by value into several dependents, which
+	// then don't see each other's changes.
+	FindingCopied FindingKind = "copied"
+	// FindingUnsafeWrite is an unexported field of a type from outside the
+	// main module written by the container.
+	FindingUnsafeWrite FindingKind = "unsafe-write"
+	// FindingCycle is a dependency cycle, which only deferred dependencies,
+	// swaps and factories could have let through.
+	FindingCycle FindingKind = "cycle"
+)
+
+// Finding is a problem found by Doctor.
+type Finding struct {
+	Kind    FindingKind `json:"kind"`
+	Bean    string      `json:"bean"`
+	Message string      `json:"message"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s: %s", f.Kind, f.Bean, f.Message)
+}
+
+// _mainModule is the path of the main module, types of other modules are foreign.
+var _mainModule = mainModule()
+
+func mainModule() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		return info.Main.Path
+	}
+	return ""
+}
+
+// Doctor checks the live graph for common problems, like go vet does for
+// code, and returns the findings by kind then registration order. Findings
+// are hints rather than errors: a bean only looked up with Find is unresolved.
+func (c *Container) Doctor() []Finding {
+	c.mu.RLock()
+	nodes := c.published()
+	order := append([]string(nil), c.order...)
+	deps := make(map[string][]string, len(c.deps))
+	for name, d := range c.deps {
+		deps[name] = append([]string(nil), d...)
+	}
+	injected := append([]injectedField(nil), c.injected...)
+	values := append([]valueBinding(nil), c.values...)
+	c.mu.RUnlock()
+
+	var findings []Finding
+	findings = append(findings, unresolvedBeans(nodes, order, deps)...)
+	findings = append(findings, copiedBeans(nodes, order, injected)...)
+	findings = append(findings, unsafeWrites(injected, values)...)
+	findings = append(findings, dependencyCycles(order, deps)...)
+	return findings
+}
+
+func unresolvedBeans(nodes map[string]interface{}, order []string, deps map[string][]string) []Finding {
+	resolved := make(map[string]bool)
+	for _, d := range deps {
+		for _, name := range d {
+			resolved[name] = true
+		}
+	}
+	var findings []Finding
+	for _, name := range order {
+		if lazy, ok := nodes[name].(*lazyBean); ok && lazy.peek() == nil {
+			findings = append(findings, Finding{Kind: FindingUnresolved, Bean: name, Message: "factory was never built"})
+			continue
+		}
+		if !resolved[name] {
+			findings = append(findings, Finding{Kind: FindingUnresolved, Bean: name, Message: "not injected into any bean"})
+		}
+	}
+	return findings
+}
+
+// copiedBeans finds the pointer beans whose struct was copied into the fields of several dependents.
+func copiedBeans(nodes map[string]interface{}, order []string, injected []injectedField) []Finding {
+	copies := make(map[string][]string)
+	for _, f := range injected {
+		bean := nodes[f.source]
+		if lazy, ok := bean.(*lazyBean); ok {
+			bean = lazy.peek()
+		}
+		typ := reflect.TypeOf(bean)
+		if typ == nil || typ.Kind() != reflect.Ptr {
+			continue
+		}
+		st := reflect.TypeOf(f.ptr).Elem()
+		if st.Field(f.field).Type == typ.Elem() {
+			copies[f.source] = append(copies[f.source], fmt.Sprintf("%s.%s", f.bean, st.Field(f.field).Name))
+		}
+	}
+	var findings []Finding
+	for _, name := range order {
+		if fields := copies[name]; len(fields) > 1 {
+			findings = append(findings, Finding{Kind: FindingCopied, Bean: name,
+				Message: fmt.Sprintf("copied by value into %s", strings.Join(fields, ", "))})
+		}
+	}
+	return findings
+}
+
+// unsafeWrites finds the unexported fields of foreign types which were written.
+func unsafeWrites(injected []injectedField, values []valueBinding) []Finding {
+	var findings []Finding
+	check := func(bean string, ptr interface{}, i int) {
+		st := reflect.TypeOf(ptr).Elem()
+		field := st.Field(i)
+		if field.PkgPath == "" || !foreign(st.PkgPath()) {
+			return
+		}
+		findings = append(findings, Finding{Kind: FindingUnsafeWrite, Bean: bean,
+			Message: fmt.Sprintf("wrote unexported field %s.%s of package %s", st.Name(), field.Name, st.PkgPath())})
+	}
+	for _, f := range injected {
+		check(f.bean, f.ptr, f.field)
+	}
+	for _, v := range values {
+		check(v.bean, v.ptr, v.field)
+	}
+	return findings
+}
+
+// foreign reports whether pkg is outside the main module, unknown when the
+// binary has no build info.
+func foreign(pkg string) bool {
+	if _mainModule == "" {
+		return false
+	}
+	return pkg != _mainModule && !strings.HasPrefix(pkg, _mainModule+"/")
+}
+
+// dependencyCycles reports every cycle once, from its first registered bean.
+func dependencyCycles(order []string, deps map[string][]string) []Finding {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int, len(order))
+	var findings []Finding
+	var path []string
+	var visit func(name string)
+	visit = func(name string) {
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range deps[name] {
+			switch state[dep] {
+			case unvisited:
+				visit(dep)
+			case visiting:
+				start := 0
+				for path[start] != dep {
+					start++
+				}
+				cycle := append(append([]string(nil), path[start:]...), dep)
+				findings = append(findings, Finding{Kind: FindingCycle, Bean: dep,
+					Message: strings.Join(cycle, " -> ")})
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = done
+	}
+	for _, name := range order {
+		if state[name] == unvisited {
+			visit(name)
+		}
+	}
+	return findings
+}
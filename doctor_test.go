@@ -0,0 +1,77 @@
+package keeper
+
+import "testing"
+
+type cyclicA struct {
+	b *cyclicB `name:"b"`
+}
+
+type cyclicB struct {
+	a *cyclicA `name:"a"`
+}
+
+func findingsOf(findings []Finding, kind FindingKind) []Finding {
+	var found []Finding
+	for _, f := range findings {
+		if f.Kind == kind {
+			found = append(found, f)
+		}
+	}
+	return found
+}
+
+func TestContainer_DoctorCopied(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	c.Register(&HelloSrv{word: "hello"}, Name("helloService"))
+	c.Register(new(HelloCtl), Name("ctl1"))
+	c.Register(new(HelloCtl), Name("ctl2"))
+	findings := c.Doctor()
+	copied := findingsOf(findings, FindingCopied)
+	if len(copied) != 1 || copied[0].Bean != "helloService" {
+		t.Fatalf("got %v", findings)
+	}
+	if unresolved := findingsOf(findings, FindingUnresolved); len(unresolved) != 2 {
+		t.Fatalf("got %v, want ctl1 and ctl2 unresolved", unresolved)
+	}
+}
+
+func TestContainer_DoctorCycle(t *testing.T) {
+	unsafeOnly(t)
+	c := New(OnMissing(MissingDefer))
+	c.Register(new(cyclicA), Name("a"))
+	c.Register(new(cyclicB), Name("b"))
+	if err := c.Build(); err != nil {
+		t.Fatal(err)
+	}
+	cycles := findingsOf(c.Doctor(), FindingCycle)
+	if len(cycles) != 1 || cycles[0].Message != "a -> b -> a" {
+		t.Fatalf("got %v", cycles)
+	}
+}
+
+func TestContainer_DoctorUnsafeWrite(t *testing.T) {
+	unsafeOnly(t)
+	defer func(m string) { _mainModule = m }(_mainModule)
+	c := New()
+	c.Register(&HelloSrv{word: "hello"}, Name("helloService"))
+	c.Register(new(HelloCtl), Name("ctl"))
+
+	_mainModule = "github.com/tooky0630/keeper"
+	if found := findingsOf(c.Doctor(), FindingUnsafeWrite); len(found) != 0 {
+		t.Fatalf("got %v, the main module isn't foreign", found)
+	}
+	_mainModule = "example.com/app"
+	if found := findingsOf(c.Doctor(), FindingUnsafeWrite); len(found) != 1 || found[0].Bean != "ctl" {
+		t.Fatalf("got %v", found)
+	}
+}
+
+func TestContainer_DoctorLazy(t *testing.T) {
+	c := New()
+	c.RegisterFactory(func(Keeper) (interface{}, error) { return &HelloSrv{}, nil }, Name("lazy"))
+	found := findingsOf(c.Doctor(), FindingUnresolved)
+	if len(found) != 1 || found[0].Message != "factory was never built" {
+		t.Fatalf("got %v", found)
+	}
+}
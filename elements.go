@@ -0,0 +1,92 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// LoadElements is a RegisterOption for beans which are collections, like the
+// handlers of a router: each element of a slice, array or map bean, or of the
+// one a pointer bean points to, gets its dependencies injected and is
+// initialized, as if it was registered under the name of the bean. The
+// elements must be pointers to structs, or the structs of a slice or of an
+// array behind a pointer; nil elements are skipped. InitOnStart applies to
+// the collection only, the elements are initialized on registration.
+//
+//	c.Register([]*Handler{{Path: "/users"}, {Path: "/orders"}}, keeper.Name("handlers"), keeper.LoadElements())
+func LoadElements() RegisterOption {
+	return registerOptionFunc(func(options *registerOptions) {
+		options.Elements = true
+	})
+}
+
+// loadElements loads the elements of the collection bean, then initializes it.
+func (c *Container) loadElements(ctx context.Context, bean interface{}, options registerOptions) (wiring, error) {
+	var w wiring
+	v := reflect.ValueOf(bean)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	elemOptions := options
+	elemOptions.InitOnStart = false
+	load := func(key string, elem reflect.Value) error {
+		if elem.Kind() == reflect.Interface {
+			elem = elem.Elem()
+		}
+		switch {
+		case !elem.IsValid() || elem.Kind() == reflect.Ptr && elem.IsNil():
+			return nil
+		case elem.Kind() == reflect.Ptr && elem.Elem().Kind() == reflect.Struct:
+		case elem.Kind() == reflect.Struct && elem.CanAddr():
+			elem = elem.Addr()
+		default:
+			return fmt.Errorf("cannot load element %s of %s, %v isn't a pointer to struct", key, options.Name, elem.Type())
+		}
+		ew, err := c.load(ctx, elem.Interface(), elemOptions)
+		if err != nil {
+			return fmt.Errorf("failed to load element %s of %s: %w", key, options.Name, err)
+		}
+		w.merge(ew)
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := load(strconv.Itoa(i), v.Index(i)); err != nil {
+				return w, err
+			}
+		}
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		values := make(map[string]reflect.Value, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			key := fmt.Sprint(iter.Key().Interface())
+			keys = append(keys, key)
+			values[key] = iter.Value()
+		}
+		sort.Strings(keys) // elements are loaded in a stable order
+		for _, key := range keys {
+			if err := load(strconv.Quote(key), values[key]); err != nil {
+				return w, err
+			}
+		}
+	default:
+		return w, fmt.Errorf("cannot load the elements of %s, %T isn't a collection", options.Name, bean)
+	}
+	initialized, err := c.initialize(ctx, bean, options, w.unresolved)
+	w.initOnStart = !initialized
+	return w, err
+}
+
+// merge adds the wiring of o to w.
+func (w *wiring) merge(o wiring) {
+	w.deps = append(w.deps, o.deps...)
+	w.values = append(w.values, o.values...)
+	w.deferred = append(w.deferred, o.deferred...)
+	w.fields = append(w.fields, o.fields...)
+	w.optional = append(w.optional, o.optional...)
+	w.unresolved = append(w.unresolved, o.unresolved...)
+}
@@ -0,0 +1,36 @@
+package keeper
+
+import (
+	"strings"
+	"testing"
+)
+
+type route struct {
+	path     string
+	helloSrv *HelloSrv `name:"helloService"`
+}
+
+func TestContainer_LoadElements(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	srv := &HelloSrv{}
+	c.Register(srv, Name("helloService"))
+	routes := []route{{path: "/users"}, {path: "/orders"}}
+	if err := c.Register(routes, Name("routes"), LoadElements()); err != nil {
+		t.Fatal(err)
+	}
+	byPath := map[string]*route{"users": {}, "orders": nil}
+	if err := c.Register(byPath, Name("routesByPath"), LoadElements()); err != nil {
+		t.Fatal(err)
+	}
+	if routes[0].helloSrv != srv || routes[1].helloSrv != srv || byPath["users"].helloSrv != srv {
+		t.Fatal("elements should be injected")
+	}
+	if log := c.AuditLog(); !strings.Contains(log, "routes.helloSrv <- helloService") {
+		t.Fatalf("got %s", log)
+	}
+	err := c.Register([]int{1}, Name("numbers"), LoadElements())
+	if err == nil || !strings.Contains(err.Error(), "element 0 of numbers") {
+		t.Fatalf("got %v", err)
+	}
+}
@@ -0,0 +1,22 @@
+package keeper
+
+import "strings"
+
+// multiError collects the failures of operations that keep going after an error.
+type multiError []error
+
+func (m multiError) Error() string {
+	msgs := make([]string, len(m))
+	for i, err := range m {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// errOrNil returns m as an error, or nil when nothing failed.
+func (m multiError) errOrNil() error {
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
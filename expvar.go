@@ -0,0 +1,69 @@
+package keeper
+
+import (
+	"expvar"
+	"sync/atomic"
+)
+
+// ExpVars is an Option that publishes the statistics of the container to
+// expvar under prefix, served as JSON by the /debug/vars handler of expvar:
+// the number of beans, the lookups of beans by Find and injection, the
+// lookups which found nothing, the registrations, failed or not, and the
+// swaps. A prefix can't be published twice, a container given a prefix
+// already published logs it and publishes nothing. Expvar has no way to
+// unpublish, the statistics of a closed container stay published.
+func ExpVars(prefix string) Option {
+	return optionFunc(func(c *Container) {
+		c.vars = &containerVars{prefix: prefix}
+	})
+}
+
+// containerVars counts the reads and writes of a container.
+type containerVars struct {
+	prefix        string
+	lookups       atomic.Int64
+	misses        atomic.Int64
+	registrations atomic.Int64
+	failures      atomic.Int64
+	swaps         atomic.Int64
+}
+
+// publishVars publishes the statistics of the container to expvar.
+func (c *Container) publishVars() {
+	if expvar.Get(c.vars.prefix) != nil {
+		c.logger.Printf("keeper: expvar %s is already published", c.vars.prefix)
+		return
+	}
+	expvar.Publish(c.vars.prefix, expvar.Func(func() interface{} {
+		return map[string]int64{
+			"beans":         int64(len(c.published())),
+			"lookups":       c.vars.lookups.Load(),
+			"misses":        c.vars.misses.Load(),
+			"registrations": c.vars.registrations.Load(),
+			"failures":      c.vars.failures.Load(),
+			"swaps":         c.vars.swaps.Load(),
+		}
+	}))
+}
+
+// countLookup counts a lookup of a bean, missed when bean is nil.
+func (c *Container) countLookup(bean interface{}) {
+	if c.vars == nil {
+		return
+	}
+	c.vars.lookups.Add(1)
+	if bean == nil {
+		c.vars.misses.Add(1)
+	}
+}
+
+// countRegistration counts a registration, failed when err is set.
+func (c *Container) countRegistration(err error) {
+	if c.vars == nil {
+		return
+	}
+	c.vars.registrations.Add(1)
+	if err != nil {
+		c.vars.failures.Add(1)
+	}
+}
@@ -0,0 +1,35 @@
+package keeper
+
+import (
+	"bytes"
+	"encoding/json"
+	"expvar"
+	"log"
+	"testing"
+)
+
+func TestExpVars(t *testing.T) {
+	c := New(ExpVars("keeper_test_vars"))
+	c.Register(&HelloSrv{word: "hello"}, Name("helloSrv"))
+	c.Register(new(HelloSrv), Name("helloSrv"))
+	c.Find("helloSrv")
+	c.Find("nothing")
+	c.Swap("helloSrv", &HelloSrv{word: "hi"})
+
+	var got map[string]int64
+	if err := json.Unmarshal([]byte(expvar.Get("keeper_test_vars").String()), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int64{"beans": 1, "lookups": 2, "misses": 1, "registrations": 2, "failures": 1, "swaps": 1}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %d, want %d", k, got[k], v)
+		}
+	}
+
+	var logged bytes.Buffer
+	New(ExpVars("keeper_test_vars"), WithLogger(log.New(&logged, "", 0)))
+	if want := "keeper: expvar keeper_test_vars is already published\n"; logged.String() != want {
+		t.Errorf("logged %q, want %q", logged.String(), want)
+	}
+}
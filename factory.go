@@ -0,0 +1,73 @@
+package keeper
+
+import (
+	"fmt"
+	"time"
+)
+
+// TTL is a RegisterOption for factory beans: once the bean is older than ttl,
+// the next resolution builds it again. It suits cached tokens, discovery
+// results and remote config clients. Dependents which got the bean injected
+// keep the old one, so they should Find it whenever they use it: the old one
+// is destroyed once replaced if it's a Disposer.
+func TTL(ttl time.Duration) RegisterOption {
+	return registerOptionFunc(func(options *registerOptions) {
+		options.TTL = ttl
+	})
+}
+
+// Cached is a RegisterOption for factory beans memoizing the bean for ttl,
+// like TTL, as a read-through cache of expensive computed beans like compiled
+// templates or parsed schemas: when rebuilding the expired bean fails, the
+// expired one is served until a rebuild succeeds.
+func Cached(ttl time.Duration) RegisterOption {
+	return registerOptionFunc(func(options *registerOptions) {
+		options.TTL = ttl
+		options.ServeStale = true
+	})
+}
+
+// RegisterFactory registers a bean under the Name option which is built by
+// factory on its first resolution, by Find or by injection into a dependent.
+// Concurrent resolutions wait for a single build.
+func (c *Container) RegisterFactory(factory FactoryFunc, opts ...RegisterOption) error {
+	var options registerOptions
+	for _, o := range opts {
+		o.applyRegisterOption(&options)
+	}
+	if err := options.Validate(); err != nil {
+		return err
+	}
+	if factory == nil {
+		return fmt.Errorf("cannot register nil factory as %s", options.Name)
+	}
+	build := factory
+	factory = func(k Keeper) (bean interface{}, err error) {
+		err = c.measure(options.Name, func() error {
+			if bean, err = build(k); err != nil {
+				return err
+			}
+			return options.checkImplements(bean)
+		})
+		return bean, err
+	}
+	return c.register(&lazyBean{factory: factory, ttl: options.TTL, serveStale: options.ServeStale}, options)
+}
+
+// build returns the bean of the factory, or nil when the build fails.
+func (c *Container) build(name string, lazy *lazyBean) interface{} {
+	v, _ := c.buildErr(nil, name, lazy)
+	return v
+}
+
+// buildErr is build returning the error of the failed build, within scope
+// when a factory resolves the bean.
+func (c *Container) buildErr(scope *buildScope, name string, lazy *lazyBean) (interface{}, error) {
+	v, err := lazy.get(c, scope)
+	if err != nil {
+		c.logger.Printf("keeper: failed to build %s: %v", name, err)
+		c.recordError(name, err)
+		return nil, err
+	}
+	return v, nil
+}
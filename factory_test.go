@@ -0,0 +1,160 @@
+package keeper
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type token struct {
+	serial int32
+}
+
+func TestContainer_RegisterFactory(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	var builds int32
+	err := c.RegisterFactory(func(Keeper) (interface{}, error) {
+		atomic.AddInt32(&builds, 1)
+		time.Sleep(time.Millisecond)
+		return &HelloSrv{word: "lazy"}, nil
+	}, Name("helloService"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info := c.Beans()[0]; !info.Lazy || info.Type != "" {
+		t.Fatalf("got %+v before build", info)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Find("helloService")
+		}()
+	}
+	wg.Wait()
+	ctl := new(HelloCtl)
+	if err := c.Register(ctl, Name("helloCtl")); err != nil {
+		t.Fatal(err)
+	}
+	if ctl.helloSrv.word != "lazy" || builds != 1 {
+		t.Fatalf("got %q after %d builds", ctl.helloSrv.word, builds)
+	}
+	if info := c.Beans()[0]; info.Type != "*keeper.HelloSrv" {
+		t.Fatalf("got %+v after build", info)
+	}
+}
+
+func TestContainer_RegisterFactoryTTL(t *testing.T) {
+	c := New()
+	var serial int32
+	c.RegisterFactory(func(Keeper) (interface{}, error) {
+		return &token{serial: atomic.AddInt32(&serial, 1)}, nil
+	}, Name("token"), TTL(20*time.Millisecond))
+	first := c.Find("token").(*token)
+	if c.Find("token").(*token) != first {
+		t.Fatal("token should be cached before expiry")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if c.Find("token").(*token).serial != 2 {
+		t.Fatal("token should be rebuilt after expiry")
+	}
+}
+
+func TestContainer_RegisterFactoryError(t *testing.T) {
+	c := New()
+	c.RegisterFactory(func(Keeper) (interface{}, error) {
+		return nil, errors.New("unreachable")
+	}, Name("helloService"))
+	if c.Find("helloService") != nil {
+		t.Fatal("failed build should not be found")
+	}
+	if err := c.Register(new(HelloCtl), Name("helloCtl")); err == nil {
+		t.Fatal("expected dependent to fail")
+	}
+}
+
+func TestContainer_RegisterFactoryCached(t *testing.T) {
+	c := New()
+	var serial int32
+	c.RegisterFactory(func(Keeper) (interface{}, error) {
+		n := atomic.AddInt32(&serial, 1)
+		if n == 2 {
+			return nil, errors.New("schema registry unavailable")
+		}
+		return &token{serial: n}, nil
+	}, Name("schema"), Cached(20*time.Millisecond))
+	first := c.Find("schema").(*token)
+	time.Sleep(30 * time.Millisecond)
+	if c.Find("schema").(*token) != first {
+		t.Fatal("expired bean should be served while the rebuild fails")
+	}
+	if c.Find("schema").(*token).serial != 3 {
+		t.Fatal("bean should be rebuilt once the factory recovers")
+	}
+}
+
+func TestContainer_RegisterFactoryReentrant(t *testing.T) {
+	c := New()
+	c.RegisterFactory(func(k Keeper) (interface{}, error) {
+		if k.Find("recursive") == nil {
+			return nil, errors.New("recursive isn't built yet")
+		}
+		return new(HelloSrv), nil
+	}, Name("recursive"))
+	done := make(chan interface{})
+	go func() { done <- c.Find("recursive") }()
+	select {
+	case bean := <-done:
+		if bean != nil {
+			t.Fatalf("got %v from a factory resolving itself", bean)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("a factory resolving its own bean deadlocks")
+	}
+}
+
+func TestContainer_RegisterFactoryTTLDestroy(t *testing.T) {
+	c := New()
+	var clients []*closableClient
+	c.RegisterFactory(func(Keeper) (interface{}, error) {
+		cl := new(closableClient)
+		clients = append(clients, cl)
+		return cl, nil
+	}, Name("token"), TTL(time.Millisecond))
+	c.Find("token")
+	time.Sleep(5 * time.Millisecond)
+	c.Find("token")
+	if len(clients) != 2 || !clients[0].closed || clients[1].closed {
+		t.Fatalf("the expired bean should be destroyed once replaced, got %d builds", len(clients))
+	}
+}
+
+func TestContainer_RegisterFactoryReentrantChain(t *testing.T) {
+	c := New()
+	c.RegisterFactory(func(k Keeper) (interface{}, error) {
+		if k.Find("b") == nil {
+			return nil, errors.New("b isn't built")
+		}
+		return new(HelloSrv), nil
+	}, Name("a"))
+	c.RegisterFactory(func(k Keeper) (interface{}, error) {
+		if k.Find("a") == nil {
+			return nil, errors.New("a isn't built")
+		}
+		return new(HelloSrv), nil
+	}, Name("b"))
+	done := make(chan interface{})
+	go func() { done <- c.Find("a") }()
+	select {
+	case bean := <-done:
+		if bean != nil {
+			t.Fatalf("got %v from factories resolving each other", bean)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("factories resolving each other deadlock")
+	}
+}
@@ -0,0 +1,22 @@
+//go:build keeper_safe
+// +build keeper_safe
+
+package keeper
+
+import (
+	"errors"
+	"reflect"
+)
+
+// Built with the keeper_safe tag, keeper doesn't use package unsafe, for
+// sandboxes and audits forbidding it. Only exported fields could be injected.
+
+var errUnexportedField = errors.New("cannot inject unexported field in keeper_safe build")
+
+// settable returns fv, which must be an exported field.
+func settable(fv reflect.Value) (reflect.Value, error) {
+	if !fv.CanSet() {
+		return reflect.Value{}, errUnexportedField
+	}
+	return fv, nil
+}
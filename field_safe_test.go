@@ -0,0 +1,53 @@
+//go:build keeper_safe
+// +build keeper_safe
+
+package keeper
+
+import "testing"
+
+func unsafeOnly(t *testing.T) {
+	t.Helper()
+	t.Skip("injects unexported fields, which keeper_safe builds refuse")
+}
+
+type ExportedCtl struct {
+	HelloSrv *HelloSrv `name:"helloService"`
+}
+
+func TestContainer_RegisterSafe(t *testing.T) {
+	c := New()
+	c.Register(new(HelloSrv), Name("helloService"))
+	ctl := new(ExportedCtl)
+	if err := c.Register(ctl, Name("exported")); err != nil {
+		t.Fatal(err)
+	}
+	if ctl.HelloSrv == nil {
+		t.Fatal("exported field should be injected")
+	}
+	if err := c.Register(new(HelloCtl), Name("helloCtl")); err == nil {
+		t.Fatal("unexported field should be refused")
+	}
+}
+
+type exportedServer struct {
+	Addr   string          `value:"http.addr"`
+	Config *greetingConfig `name:"greetingConfig"`
+}
+
+func TestContainer_SwapSafe(t *testing.T) {
+	c := New(WithConfig(&mapSource{values: map[string]string{"http.addr": ":8080"}}))
+	c.Register(&greetingConfig{word: "hello"}, Name("greetingConfig"))
+	srv := new(exportedServer)
+	if err := c.Register(srv, Name("server")); err != nil {
+		t.Fatal(err)
+	}
+	if srv.Addr != ":8080" {
+		t.Fatalf("got addr %q", srv.Addr)
+	}
+	if _, err := c.Swap("greetingConfig", &greetingConfig{word: "bonjour"}); err != nil {
+		t.Fatal(err)
+	}
+	if srv.Config.word != "bonjour" {
+		t.Fatalf("dependent got %q", srv.Config.word)
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !keeper_safe
+// +build !keeper_safe
+
+package keeper
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// settable returns fv as a settable value, unexported fields included.
+func settable(fv reflect.Value) (reflect.Value, error) {
+	return reflect.NewAt(fv.Type(), unsafe.Pointer(fv.UnsafeAddr())).Elem(), nil
+}
@@ -0,0 +1,10 @@
+//go:build !keeper_safe
+// +build !keeper_safe
+
+package keeper
+
+import "testing"
+
+// unsafeOnly skips the tests whose fixtures inject unexported fields in
+// keeper_safe builds, it does nothing otherwise.
+func unsafeOnly(t *testing.T) {}
@@ -0,0 +1,74 @@
+package keeper
+
+import (
+	"reflect"
+	"sort"
+)
+
+// FieldStats counts the outcomes of injecting a name tagged field, over all
+// the beans of its type. Optional fields which are never hit, in any
+// environment, are dead dependencies.
+type FieldStats struct {
+	Type           string `json:"type"` // the package qualified struct type
+	Field          string `json:"field"`
+	Optional       bool   `json:"optional,omitempty"`
+	Hits           int    `json:"hits"`           // injected
+	OptionalMisses int    `json:"optionalMisses"` // left zero, being optional
+	Misses         int    `json:"misses"`         // left zero by MissingWarn or MissingDefer
+	Errors         int    `json:"errors"`
+}
+
+type fieldKey struct {
+	typ   reflect.Type
+	field string
+}
+
+// TrackFields is an Option that counts the outcomes of injecting each name
+// tagged field, reported by FieldStats.
+func TrackFields() Option {
+	return optionFunc(func(c *Container) {
+		c.trackFields = true
+	})
+}
+
+// FieldStats returns the outcomes of the injected fields sorted by type and
+// field, it's empty unless TrackFields is set.
+func (c *Container) FieldStats() []FieldStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	stats := make([]FieldStats, 0, len(c.fieldStats))
+	for _, s := range c.fieldStats {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Type != stats[j].Type {
+			return stats[i].Type < stats[j].Type
+		}
+		return stats[i].Field < stats[j].Field
+	})
+	return stats
+}
+
+func (c *Container) recordField(typ reflect.Type, field string, optional, injected bool, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := fieldKey{typ, field}
+	s, ok := c.fieldStats[key]
+	if !ok {
+		if c.fieldStats == nil {
+			c.fieldStats = make(map[fieldKey]*FieldStats)
+		}
+		s = &FieldStats{Type: typ.PkgPath() + "." + typ.Name(), Field: field, Optional: optional}
+		c.fieldStats[key] = s
+	}
+	switch {
+	case err != nil:
+		s.Errors++
+	case injected:
+		s.Hits++
+	case optional:
+		s.OptionalMisses++
+	default:
+		s.Misses++
+	}
+}
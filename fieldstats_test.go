@@ -0,0 +1,27 @@
+package keeper
+
+import "testing"
+
+func TestContainer_FieldStats(t *testing.T) {
+	unsafeOnly(t)
+	c := New(TrackFields())
+	c.Register(new(optionalIntegration), Name("first"))
+	c.Register(&HelloSrv{}, Name("metrics"))
+	c.Register(new(optionalIntegration), Name("second"))
+	c.Register(new(HelloCtl), Name("helloCtl"))
+
+	stats := c.FieldStats()
+	if len(stats) != 2 {
+		t.Fatalf("got %+v", stats)
+	}
+	ctl, optional := stats[0], stats[1]
+	if ctl.Field != "helloSrv" || ctl.Errors != 1 || ctl.Type != "github.com/tooky0630/keeper.HelloCtl" {
+		t.Fatalf("got %+v", ctl)
+	}
+	if optional.Field != "metrics" || !optional.Optional || optional.Hits != 1 || optional.OptionalMisses != 1 {
+		t.Fatalf("got %+v", optional)
+	}
+	if len(New().FieldStats()) != 0 {
+		t.Fatal("fields should only be tracked with TrackFields")
+	}
+}
@@ -0,0 +1,70 @@
+package keeper
+
+import (
+	"context"
+	"reflect"
+)
+
+// FillOptional is an Option that keeps track of the optional dependencies
+// which were missing, and injects them into their dependents once they are
+// registered, so optional integrations could arrive late. Dependents
+// implementing AfterInjector are notified of the filled fields; they may be
+// in use meanwhile, so they should guard these fields.
+func FillOptional() Option {
+	return optionFunc(func(c *Container) {
+		c.fillOptional = true
+	})
+}
+
+// optionalField is an optional field left zero, filled by the registration
+// of one of the wanted names.
+type optionalField struct {
+	deferredField
+	wants []string
+}
+
+// fill injects the bean of name into the optional fields waiting for it.
+func (c *Container) fill(ctx context.Context, name string) {
+	if !c.fillOptional {
+		return
+	}
+	c.mu.Lock()
+	var due, pending []optionalField
+	for _, f := range c.optional {
+		if f.wanted(name) {
+			due = append(due, f)
+		} else {
+			pending = append(pending, f)
+		}
+	}
+	c.optional = pending
+	c.mu.Unlock()
+
+	for _, f := range due {
+		var w wiring
+		injected, err := c.loadField(ctx, f.ptr, f.field, f.options, MissingError, &w)
+		if err != nil {
+			c.logger.Printf("keeper: failed to fill %s of %s: %v", name, f.bean, err)
+			continue
+		}
+		if !injected {
+			continue
+		}
+		c.mu.Lock()
+		c.deps[f.bean] = append(c.deps[f.bean], w.deps...)
+		c.injected = append(c.injected, w.fields...)
+		c.mu.Unlock()
+		if after, ok := f.ptr.(AfterInjector); ok {
+			after.AfterInject(reflect.TypeOf(f.ptr).Elem().Field(f.field).Name)
+		}
+	}
+}
+
+func (f optionalField) wanted(name string) bool {
+	for _, want := range f.wants {
+		if want == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package keeper
+
+import "testing"
+
+type optionalIntegration struct {
+	metrics *HelloSrv `name:"metrics,optional"`
+	filled  []string
+}
+
+func (o *optionalIntegration) AfterInject(field string) {
+	o.filled = append(o.filled, field)
+}
+
+func TestContainer_FillOptional(t *testing.T) {
+	unsafeOnly(t)
+	c := New(FillOptional())
+	consumer := new(optionalIntegration)
+	if err := c.Register(consumer, Name("consumer")); err != nil {
+		t.Fatal(err)
+	}
+	if consumer.metrics != nil {
+		t.Fatal("missing optional dependency should be left nil")
+	}
+	c.Register(&HelloSrv{word: "late"}, Name("metrics"))
+	if consumer.metrics == nil || consumer.metrics.word != "late" {
+		t.Fatal("late dependency should be filled")
+	}
+	if len(consumer.filled) != 1 || consumer.filled[0] != "metrics" {
+		t.Fatalf("got filled %v", consumer.filled)
+	}
+	if deps := c.Beans()[0].Dependencies; len(deps) != 1 || deps[0] != "metrics" {
+		t.Fatalf("got dependencies %v", deps)
+	}
+}
+
+func TestContainer_FillOptionalDisabled(t *testing.T) {
+	c := New()
+	consumer := new(optionalIntegration)
+	c.Register(consumer, Name("consumer"))
+	c.Register(&HelloSrv{}, Name("metrics"))
+	if consumer.metrics != nil {
+		t.Fatal("optional dependency should only be filled with FillOptional")
+	}
+}
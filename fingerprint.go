@@ -0,0 +1,50 @@
+package keeper
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Fingerprint returns the hash of the wiring of the graph: the Fingerprint of
+// each bean, whatever the order of registration. Instances of a service built
+// alike have the same fingerprint, logging it at startup shows the drift of
+// a canary from its baseline, and BeanInfo tells which beans drifted.
+func (c *Container) Fingerprint() string {
+	infos := c.Beans()
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	h := sha256.New()
+	for _, info := range infos {
+		fmt.Fprintf(h, "%s=%s\n", info.Name, info.Fingerprint)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// beanFingerprint hashes the wiring of a bean: its name, type, options and
+// dependencies. Factory beans are hashed without their type, which is only
+// known once built, nor are descriptions and registration sites hashed.
+func beanFingerprint(name, typ string, lazy bool, opts registerOptions, deps []string) string {
+	h := sha256.New()
+	if lazy {
+		typ = "lazy"
+	}
+	fmt.Fprintf(h, "name=%s\ntype=%s\n", name, typ)
+	fmt.Fprintf(h, "adopted=%t\nreadOnly=%t\ninitOnStart=%t\nnoInit=%t\nelements=%t\n", opts.adopted, opts.ReadOnly, opts.InitOnStart, opts.NoInit, opts.Elements)
+	fmt.Fprintf(h, "group=%s\nweight=%d\nttl=%v\nserveStale=%t\n", opts.Group, opts.Weight, opts.TTL, opts.ServeStale)
+	fmt.Fprintf(h, "requires=%s\ngrants=%s\n", strings.Join(opts.Requires, ","), strings.Join(opts.Grants, ","))
+	keys := make([]string, 0, len(opts.Labels))
+	for k := range opts.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(h, "label %s=%s\n", k, opts.Labels[k])
+	}
+	for _, dep := range deps { // in the order of the fields
+		io.WriteString(h, "dep="+dep+"\n")
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
@@ -0,0 +1,34 @@
+package keeper
+
+import "testing"
+
+func TestFingerprint(t *testing.T) {
+	build := func(ordering Ordering, label string) Keeper {
+		c := New(WithOrdering(ordering))
+		c.Register(&HelloSrv{}, Name("helloService"), Label("tier", label))
+		c.Register(new(HelloCtl), Name("helloCtl"))
+		return c
+	}
+	baseline, canary := build(RegistrationOrder, "web"), build(NameOrder, "web")
+	if baseline.Fingerprint() != canary.Fingerprint() {
+		t.Error("graphs wired alike should have the same fingerprint")
+	}
+	drifted := build(RegistrationOrder, "batch")
+	if baseline.Fingerprint() == drifted.Fingerprint() {
+		t.Error("a changed label should change the fingerprint")
+	}
+	fingerprints := func(k Keeper) map[string]string {
+		m := make(map[string]string)
+		for _, info := range k.Beans() {
+			m[info.Name] = info.Fingerprint
+		}
+		return m
+	}
+	got, want := fingerprints(drifted), fingerprints(baseline)
+	if got["helloService"] == want["helloService"] {
+		t.Error("helloService drifted")
+	}
+	if got["helloCtl"] != want["helloCtl"] {
+		t.Error("helloCtl didn't drift")
+	}
+}
@@ -0,0 +1,134 @@
+package keeper
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FlagSource reports whether the runtime feature flags are enabled, like the
+// client of a feature flag service.
+type FlagSource interface {
+	Enabled(flag string) bool
+}
+
+// FlagWatcher is implemented by a FlagSource which could report its changes;
+// onChange receives the changed flags, or nil when any flag may have changed.
+type FlagWatcher interface {
+	Watch(onChange func(flags []string))
+}
+
+// WithFlags is an Option that gates the beans registered with IfFlag by the
+// flags of src, and swaps them whenever src reports a change.
+func WithFlags(src FlagSource) Option {
+	return optionFunc(func(c *Container) {
+		c.flags = src
+		if w, ok := src.(FlagWatcher); ok {
+			w.Watch(c.reloadFlags)
+		}
+	})
+}
+
+// IfFlag is a RegisterOption that puts the bean behind the runtime feature
+// flag: the bean serves its name while the flag is enabled, and otherwise,
+// loaded with the same options, serves it while the flag is disabled, or
+// when the container has no FlagSource. The flag is evaluated on
+// registration and on Build, and again whenever the FlagSource reports a
+// change, swapping the beans like Swap does, so dependents should guard the
+// gated fields. Beans swapped out are kept for the flag to come back, they're
+// never destroyed:
+//
+//	c.Register(newBilling, keeper.Name("billing"), keeper.IfFlag("new-billing", legacyBilling))
+func IfFlag(flag string, otherwise interface{}) RegisterOption {
+	return registerOptionFunc(func(options *registerOptions) {
+		options.Flag = flag
+		options.Otherwise = otherwise
+	})
+}
+
+// flagGate is a bean registered with IfFlag.
+type flagGate struct {
+	flag    string
+	on, off interface{}
+	enabled bool // on serves the name
+}
+
+func (opt registerOptions) validateFlag() error {
+	if opt.Flag != "" && opt.Otherwise == nil {
+		return fmt.Errorf("IfFlag(%q) of %s needs a bean to serve while the flag is disabled", opt.Flag, opt.Name)
+	}
+	return nil
+}
+
+// gate records the bean registered with IfFlag, and swaps it out when its
+// flag is disabled.
+func (c *Container) gate(bean interface{}, options registerOptions) error {
+	c.mu.Lock()
+	if c.gates == nil {
+		c.gates = make(map[string]*flagGate)
+	}
+	c.gates[options.Name] = &flagGate{flag: options.Flag, on: bean, off: options.Otherwise, enabled: true}
+	c.mu.Unlock()
+	return c.evaluateFlags([]string{options.Flag})
+}
+
+// evaluateFlags swaps the gated beans whose flag changed, of the flags given
+// or of all of them when flags is nil.
+func (c *Container) evaluateFlags(flags []string) error {
+	changed := make(map[string]bool, len(flags))
+	for _, flag := range flags {
+		changed[flag] = true
+	}
+	c.mu.RLock()
+	names := make([]string, 0, len(c.gates))
+	for _, name := range c.order {
+		if g, ok := c.gates[name]; ok && (flags == nil || changed[g.flag]) {
+			names = append(names, name)
+		}
+	}
+	c.mu.RUnlock()
+	var errs []string
+	for _, name := range names {
+		if err := c.evaluateFlag(name); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to evaluate flags: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// evaluateFlag swaps in the bean of name its flag selects.
+func (c *Container) evaluateFlag(name string) error {
+	c.mu.RLock()
+	g, flags := c.gates[name], c.flags
+	c.mu.RUnlock()
+	enabled := flags != nil && flags.Enabled(g.flag)
+	c.mu.Lock()
+	if enabled == g.enabled {
+		c.mu.Unlock()
+		return nil
+	}
+	g.enabled = enabled
+	c.mu.Unlock()
+	bean := g.off
+	if enabled {
+		bean = g.on
+	}
+	if _, err := c.Swap(name, bean); err != nil {
+		c.mu.Lock()
+		g.enabled = !enabled
+		c.mu.Unlock()
+		c.recordError(name, err)
+		return fmt.Errorf("flag %s of %s: %v", g.flag, name, err)
+	}
+	return nil
+}
+
+// reloadFlags swaps the gated beans whose flag changed, on the report of a
+// FlagWatcher.
+func (c *Container) reloadFlags(flags []string) {
+	if err := c.evaluateFlags(flags); err != nil {
+		c.logger.Printf("keeper: %v", err)
+	}
+}
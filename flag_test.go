@@ -0,0 +1,75 @@
+package keeper
+
+import (
+	"sync"
+	"testing"
+)
+
+type fakeFlags struct {
+	mu       sync.Mutex
+	enabled  map[string]bool
+	onChange func(flags []string)
+}
+
+func (f *fakeFlags) Enabled(flag string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.enabled[flag]
+}
+
+func (f *fakeFlags) Watch(onChange func(flags []string)) { f.onChange = onChange }
+
+func (f *fakeFlags) set(flag string, enabled bool) {
+	f.mu.Lock()
+	f.enabled[flag] = enabled
+	f.mu.Unlock()
+	f.onChange([]string{flag})
+}
+
+func TestIfFlag(t *testing.T) {
+	unsafeOnly(t)
+	flags := &fakeFlags{enabled: map[string]bool{}}
+	c := New(WithFlags(flags))
+	newSrv, legacySrv := &HelloSrv{word: "new"}, &HelloSrv{word: "legacy"}
+	if err := c.Register(newSrv, Name("helloService"), IfFlag("new-hello", legacySrv)); err != nil {
+		t.Fatal(err)
+	}
+	ctl := new(HelloCtl)
+	if err := c.Register(ctl, Name("helloCtl")); err != nil {
+		t.Fatal(err)
+	}
+	if ctl.helloSrv.word != "legacy" {
+		t.Fatalf("got %q while the flag is disabled", ctl.helloSrv.word)
+	}
+
+	flags.set("new-hello", true)
+	if c.Find("helloService") != newSrv || ctl.helloSrv.word != "new" {
+		t.Fatalf("got %q once the flag is enabled", ctl.helloSrv.word)
+	}
+	flags.set("new-hello", false)
+	if c.Find("helloService") != legacySrv || ctl.helloSrv.word != "legacy" {
+		t.Fatalf("got %q once the flag is disabled again", ctl.helloSrv.word)
+	}
+
+	flags.mu.Lock()
+	flags.enabled["new-hello"] = true
+	flags.mu.Unlock()
+	if err := c.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if c.Find("helloService") != newSrv {
+		t.Fatal("Build should evaluate the flags")
+	}
+}
+
+func TestIfFlag_NoSource(t *testing.T) {
+	c := New()
+	legacy := &HelloSrv{word: "legacy"}
+	c.Register(&HelloSrv{word: "new"}, Name("helloService"), IfFlag("new-hello", legacy))
+	if c.Find("helloService") != legacy {
+		t.Fatal("without a FlagSource, the flag should be disabled")
+	}
+	if err := c.Register(&HelloSrv{}, Name("other"), IfFlag("new-hello", nil)); err == nil {
+		t.Fatal("expected error for IfFlag without a bean to serve while disabled")
+	}
+}
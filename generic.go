@@ -0,0 +1,128 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+)
+
+// Beans of generic types are named after their instantiation: the bean of
+// *Repo[model.User] is "repo[model.User]", the type arguments keeping their
+// package name so Repo[model.User] and Repo[audit.User] don't collide.
+// TypeName gives the name, RegisterAs and Resolve use it:
+//
+//	keeper.RegisterAs(c, NewRepo[model.User](db))
+//	users, err := keeper.Resolve[*Repo[model.User]](c)
+//
+// Tags name them alike, quoted when the type has several type arguments:
+// `name:"'cache[string,model.User]'"`.
+
+// TypeName is the canonical bean name of T, the BeanName of its type:
+// "helloSrv" for *HelloSrv, "repo[model.User]" for *Repo[model.User].
+func TypeName[T any]() string {
+	return BeanName(typeName(reflect.TypeOf((*T)(nil)).Elem()))
+}
+
+// RegisterAs registers bean under the TypeName of T, unless opts name it.
+func RegisterAs[T any](k Keeper, bean T, opts ...RegisterOption) error {
+	return k.Register(bean, append([]RegisterOption{Name(TypeName[T]())}, opts...)...)
+}
+
+// Resolve returns the bean named after the TypeName of T, or else the bean
+// ResolveType finds for T.
+func Resolve[T any](k Keeper) (T, error) {
+	var zero T
+	if bean, ok := k.Find(TypeName[T]()).(T); ok {
+		return bean, nil
+	}
+	bean, err := k.ResolveType(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return zero, err
+	}
+	t, ok := bean.(T)
+	if !ok {
+		return zero, fmt.Errorf("resolved %T, not %s", bean, TypeName[T]())
+	}
+	return t, nil
+}
+
+// _importPath matches the import paths qualifying the type arguments of an
+// instantiated generic type, up to the package name.
+var _importPath = regexp.MustCompile(`[^\[\],*\s]*/`)
+
+// typeName is the name of t as written in its package, with the type
+// arguments qualified by their package name only: Repo[model.User] rather
+// than Repo[example.com/app/model.User]. Unnamed types are spelled out.
+func typeName(t reflect.Type) string {
+	if t.Kind() == reflect.Ptr && t.Name() == "" && t.Elem().Name() != "" {
+		return "*" + typeName(t.Elem())
+	}
+	name := t.Name()
+	if name == "" {
+		name = t.String()
+	}
+	return _importPath.ReplaceAllString(name, "")
+}
+
+// Factory builds a new T on every call, T being a pointer to a struct or a
+// struct: its dependencies are injected from the container, and its fields
+// tagged `ctx:"name"` from the values of ctx, which carry the arguments of
+// the caller. Fields of a Factory type are injected without a tag:
+//
+//	type Dispatcher struct {
+//		newWorker keeper.Factory[*Worker]
+//	}
+//
+//	w, err := d.newWorker(keeper.WithValue(ctx, "tenant", id))
+type Factory[T any] func(ctx context.Context) (T, error)
+
+// NewFactory returns the Factory of T building from k.
+func NewFactory[T any](k Keeper) Factory[T] {
+	return func(ctx context.Context) (T, error) {
+		var zero T
+		typ := reflect.TypeOf((*T)(nil)).Elem()
+		elem := typ
+		if typ.Kind() == reflect.Ptr {
+			elem = typ.Elem()
+		}
+		if elem.Kind() != reflect.Struct {
+			return zero, fmt.Errorf("cannot build %v, it isn't a struct", typ)
+		}
+		ptr := reflect.New(elem)
+		if err := k.ProvideContext(ctx, ptr.Interface()); err != nil {
+			return zero, err
+		}
+		if typ.Kind() == reflect.Ptr {
+			return ptr.Interface().(T), nil
+		}
+		return ptr.Elem().Interface().(T), nil
+	}
+}
+
+func (Factory[T]) factoryOf(k Keeper) interface{} {
+	return NewFactory[T](k)
+}
+
+// factoryField is implemented by the Factory types.
+type factoryField interface {
+	factoryOf(k Keeper) interface{}
+}
+
+var _factoryFieldType = reflect.TypeOf((*factoryField)(nil)).Elem()
+
+// setFactory sets the i-th field of the struct ptr points to, a Factory, if
+// it isn't set already.
+func (c *Container) setFactory(ptr interface{}, i int) error {
+	fv := reflect.ValueOf(ptr).Elem().Field(i)
+	if !fv.IsNil() {
+		return nil
+	}
+	factory := reflect.Zero(fv.Type()).Interface().(factoryField).factoryOf(c)
+	fv, err := settable(fv)
+	if err != nil {
+		return fmt.Errorf("failed to set factory %s: %v", fieldPath(ptr, i), err)
+	}
+	fv.Set(reflect.ValueOf(factory))
+	return nil
+}
@@ -0,0 +1,97 @@
+package keeper
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type user struct{}
+
+type Repo[T any] struct {
+	items []T
+}
+
+type userService struct {
+	users *Repo[user] `name:"repo[keeper.user]"`
+}
+
+func TestTypeName(t *testing.T) {
+	cases := map[string]string{
+		TypeName[*HelloSrv]():             "helloSrv",
+		TypeName[*Repo[user]]():           "repo[keeper.user]",
+		TypeName[Repo[*user]]():           "repo[*keeper.user]",
+		TypeName[map[string]Repo[user]](): "map[string]keeper.Repo[keeper.user]",
+	}
+	for got, want := range cases {
+		if got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+	if got := typeName(reflect.TypeOf(new(Repo[user]))); got != "*Repo[keeper.user]" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestRegisterAs(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	users := &Repo[user]{}
+	if err := RegisterAs(c, users); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterAs(c, &Repo[string]{}); err != nil {
+		t.Fatal(err)
+	}
+	svc := new(userService)
+	if err := c.Register(svc, Name("userService")); err != nil {
+		t.Fatal(err)
+	}
+	if svc.users != users {
+		t.Fatal("generic bean should be injected by its TypeName")
+	}
+	got, err := Resolve[*Repo[user]](c)
+	if err != nil || got != users {
+		t.Fatalf("got %v, %v", got, err)
+	}
+	_, err = Resolve[*Repo[int]](c)
+	if err == nil || !strings.Contains(err.Error(), "*keeper.Repo[int]") {
+		t.Fatalf("got %v", err)
+	}
+}
+
+type tenantWorker struct {
+	helloSrv *HelloSrv `name:"helloService"`
+	tenant   string    `ctx:"tenant"`
+}
+
+type dispatcher struct {
+	newWorker Factory[*tenantWorker]
+	newValue  Factory[tenantWorker]
+}
+
+func TestFactory(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	srv := &HelloSrv{}
+	c.Register(srv, Name("helloService"))
+	d := new(dispatcher)
+	if err := c.Register(d, Name("dispatcher")); err != nil {
+		t.Fatal(err)
+	}
+	w1, err := d.newWorker(WithValue(context.Background(), "tenant", "acme"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w2, _ := d.newWorker(WithValue(context.Background(), "tenant", "globex"))
+	if w1 == w2 || w1.helloSrv != srv || w1.tenant != "acme" || w2.tenant != "globex" {
+		t.Fatalf("got %+v and %+v", w1, w2)
+	}
+	if w, err := d.newValue(WithValue(context.Background(), "tenant", "initech")); err != nil || w.tenant != "initech" {
+		t.Fatalf("got %+v, %v", w, err)
+	}
+	if _, err := d.newWorker(context.Background()); err == nil {
+		t.Fatal("expected the missing argument to fail")
+	}
+}
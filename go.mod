@@ -1,3 +1,3 @@
 module github.com/tooky0630/keeper
 
-go 1.15
+go 1.21
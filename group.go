@@ -0,0 +1,130 @@
+package keeper
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// Group is a RegisterOption that adds the bean to the group of equivalent
+// beans, like the clients of sharded upstreams. Resolving the group name, by
+// Find or by injection, picks one of its beans with the Balancer of the group.
+//
+//	c.Register(clientA, keeper.Name("upstreamA"), keeper.Group("upstreams"), keeper.Weight(3))
+//	c.Register(clientB, keeper.Name("upstreamB"), keeper.Group("upstreams"))
+//
+//	type Proxy struct {
+//		client *Client `name:"upstreams"`
+//	}
+func Group(group string) RegisterOption {
+	return registerOptionFunc(func(options *registerOptions) {
+		options.Group = group
+	})
+}
+
+// Weight is a RegisterOption that sets the weight of the bean within its
+// group, 1 by default. Only weighted balancers care about it.
+func Weight(weight int) RegisterOption {
+	return registerOptionFunc(func(options *registerOptions) {
+		options.Weight = weight
+	})
+}
+
+// Member is a bean of a group.
+type Member struct {
+	Name   string
+	Weight int
+}
+
+// Balancer picks the member of a group for each resolution, it returns the
+// index of the member. Balancers must be safe for concurrent use.
+type Balancer interface {
+	Pick(members []Member) int
+}
+
+// WithBalancer is an Option that sets the Balancer of group, RoundRobin by default.
+func WithBalancer(group string, b Balancer) Option {
+	return optionFunc(func(c *Container) {
+		if c.balancers == nil {
+			c.balancers = make(map[string]Balancer)
+		}
+		c.balancers[group] = b
+	})
+}
+
+// RoundRobin returns a Balancer picking the members in turn, ignoring their weight.
+func RoundRobin() Balancer {
+	return new(roundRobin)
+}
+
+type roundRobin struct {
+	next uint64
+}
+
+func (r *roundRobin) Pick(members []Member) int {
+	return int((atomic.AddUint64(&r.next, 1) - 1) % uint64(len(members)))
+}
+
+// WeightedRoundRobin returns a Balancer picking the members in proportion
+// to their weight, interleaved rather than in bursts: weights of 2 and 1
+// pick a, b, a.
+func WeightedRoundRobin() Balancer {
+	return &weightedRoundRobin{current: make(map[string]int)}
+}
+
+type weightedRoundRobin struct {
+	mu      sync.Mutex
+	current map[string]int
+}
+
+// Pick is the smooth weighted round-robin of nginx.
+func (r *weightedRoundRobin) Pick(members []Member) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	best, total := -1, 0
+	for i, m := range members {
+		total += m.Weight
+		r.current[m.Name] += m.Weight
+		if best < 0 || r.current[m.Name] > r.current[members[best].Name] {
+			best = i
+		}
+	}
+	r.current[members[best].Name] -= total
+	return best
+}
+
+// beanGroup is published under the group name, it's replaced whenever a
+// member joins.
+type beanGroup struct {
+	members  []Member
+	balancer Balancer
+}
+
+// join returns the group of name with the member registered with options,
+// or an error when a bean already has the name.
+func (c *Container) join(nodes map[string]interface{}, options registerOptions) (*beanGroup, error) {
+	weight := options.Weight
+	if weight <= 0 {
+		weight = 1
+	}
+	g := &beanGroup{balancer: c.balancers[options.Group]}
+	switch old := nodes[options.Group].(type) {
+	case nil:
+		if g.balancer == nil {
+			g.balancer = RoundRobin()
+		}
+	case *beanGroup:
+		g.members = append(g.members, old.members...)
+		g.balancer = old.balancer
+	default:
+		return nil, fmt.Errorf("cannot add %s to group %s, a bean has the name", options.Name, options.Group)
+	}
+	g.members = append(g.members, Member{Name: options.Name, Weight: weight})
+	return g, nil
+}
+
+// pick resolves a member of the group, within scope when a factory
+// resolves the group.
+func (c *Container) pick(scope *buildScope, g *beanGroup) interface{} {
+	return c.lookupIn(scope, g.members[g.balancer.Pick(g.members)].Name)
+}
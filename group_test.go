@@ -0,0 +1,65 @@
+package keeper
+
+import (
+	"strings"
+	"testing"
+)
+
+type upstreamProxy struct {
+	client *HelloSrv `name:"upstreams"`
+}
+
+func picks(c Keeper, group string, n int) string {
+	var words []string
+	for i := 0; i < n; i++ {
+		words = append(words, c.Find(group).(*HelloSrv).word)
+	}
+	return strings.Join(words, " ")
+}
+
+func TestContainer_GroupRoundRobin(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	c.Register(&HelloSrv{word: "a"}, Name("upstreamA"), Group("upstreams"))
+	c.Register(&HelloSrv{word: "b"}, Name("upstreamB"), Group("upstreams"))
+	if got := picks(c, "upstreams", 4); got != "a b a b" {
+		t.Fatalf("got %s", got)
+	}
+	proxy := new(upstreamProxy)
+	if err := c.Register(proxy, Name("proxy")); err != nil {
+		t.Fatal(err)
+	}
+	if proxy.client == nil {
+		t.Fatal("a member of the group should be injected")
+	}
+	if beans := c.All(); len(beans) != 3 {
+		t.Fatalf("got %v, groups shouldn't be listed", beans)
+	}
+}
+
+func TestContainer_GroupWeighted(t *testing.T) {
+	c := New(WithBalancer("upstreams", WeightedRoundRobin()))
+	c.Register(&HelloSrv{word: "a"}, Name("upstreamA"), Group("upstreams"), Weight(2))
+	c.Register(&HelloSrv{word: "b"}, Name("upstreamB"), Group("upstreams"))
+	if got := picks(c, "upstreams", 6); got != "a b a a b a" {
+		t.Fatalf("got %s", got)
+	}
+}
+
+func TestContainer_GroupConflict(t *testing.T) {
+	c := New()
+	c.Register(&HelloSrv{}, Name("upstreams"))
+	if err := c.Register(&HelloSrv{}, Name("upstreamA"), Group("upstreams")); err == nil {
+		t.Fatal("expected error for a group named like a bean")
+	}
+	c.Register(&HelloSrv{}, Name("upstreamB"), Group("clients"))
+	if err := c.Register(&HelloSrv{}, Name("clients")); err == nil {
+		t.Fatal("expected error for a bean named like a group")
+	}
+	if err := c.Register(&HelloSrv{}, Name("self"), Group("self")); err == nil {
+		t.Fatal("expected error for a group named like its own bean")
+	}
+	if c.Find("self") != nil {
+		t.Fatal("expected the rejected bean not to be registered")
+	}
+}
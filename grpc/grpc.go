@@ -0,0 +1,53 @@
+// Package kgrpc registers keeper managed services onto a grpc.Server.
+//
+// Services implement Service, and a Registrar registered into the same
+// container collects and registers all of them when the container starts:
+//
+//	c := keeper.New()
+//	c.Register(kgrpc.NewRegistrar(c, grpc.NewServer()), keeper.Name("grpcServer"))
+//	c.Register(new(HelloService), keeper.Name("helloService"))
+//	c.Start(ctx)
+//
+// The package is named kgrpc so that it doesn't shadow google.golang.org/grpc.
+package kgrpc
+
+import (
+	"context"
+	"sort"
+
+	"github.com/tooky0630/keeper"
+	"google.golang.org/grpc"
+)
+
+// Service is implemented by beans which serve a grpc service, usually by
+// calling the generated RegisterXxxServer function.
+type Service interface {
+	RegisterService(s *grpc.Server)
+}
+
+// Registrar registers every Service bean with Server on Start.
+type Registrar struct {
+	Server *grpc.Server
+	keeper keeper.Keeper
+}
+
+// NewRegistrar returns a Registrar collecting Service beans of k.
+func NewRegistrar(k keeper.Keeper, s *grpc.Server) *Registrar {
+	return &Registrar{Server: s, keeper: k}
+}
+
+// Start registers all Service beans, in order of bean name.
+func (r *Registrar) Start(context.Context) error {
+	beans := r.keeper.All()
+	names := make([]string, 0, len(beans))
+	for name, bean := range beans {
+		if _, ok := bean.(Service); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		beans[name].(Service).RegisterService(r.Server)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package kgrpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tooky0630/keeper"
+	"google.golang.org/grpc"
+)
+
+type helloServer interface {
+	Hello() string
+}
+
+var helloServiceDesc = grpc.ServiceDesc{
+	ServiceName: "keeper.Hello",
+	HandlerType: (*helloServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams:     []grpc.StreamDesc{},
+}
+
+type helloService struct{}
+
+func (s *helloService) Hello() string { return "Hello World" }
+
+func (s *helloService) RegisterService(srv *grpc.Server) {
+	srv.RegisterService(&helloServiceDesc, s)
+}
+
+func TestRegistrar_Start(t *testing.T) {
+	c := keeper.New()
+	srv := grpc.NewServer()
+	if err := c.Register(NewRegistrar(c, srv), keeper.Name("grpcServer")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Register(new(helloService), keeper.Name("helloService")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := srv.GetServiceInfo()["keeper.Hello"]; !ok {
+		t.Fatal("hello service is not registered")
+	}
+}
@@ -0,0 +1,93 @@
+package keeper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// UnwiredError reports the use of a field left unwired because its bean is
+// missing. It's the panic value of the guards of GuardMissing.
+type UnwiredError struct {
+	Field   string // like "HelloCtl.metrics"
+	Missing string // name of the missing bean
+}
+
+func (e *UnwiredError) Error() string {
+	return fmt.Sprintf("keeper: %s is unwired, bean %s is missing", e.Field, e.Missing)
+}
+
+// GuardMissing is an Option that fills the optional fields whose bean is
+// missing with a guard reporting the UnwiredError when used, instead of a
+// nil pointer dereference far from the wiring mistake. Guards of func fields
+// return the error when the func's last result is an error, and panic with it
+// otherwise. Go can't implement an interface at runtime, so interface fields
+// are guarded by the implementation given to Guard, and left nil without one.
+// Fields of other kinds are left zero.
+func GuardMissing() Option {
+	return optionFunc(func(c *Container) {
+		c.guardMissing = true
+	})
+}
+
+// Guard is an Option that sets the guard of the interface iface points to for
+// GuardMissing: guard returns an implementation of the interface whose
+// methods return or panic with err. Generated code, or a few lines per
+// interface, provides it:
+//
+//	keeper.Guard((*Mailer)(nil), func(err error) interface{} { return mailerGuard{err} })
+func Guard(iface interface{}, guard func(err error) interface{}) Option {
+	return optionFunc(func(c *Container) {
+		typ := reflect.TypeOf(iface)
+		if typ == nil || typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Interface {
+			panic(fmt.Sprintf("keeper: Guard must be given a pointer to interface, got %v", typ))
+		}
+		if c.guards == nil {
+			c.guards = make(map[reflect.Type]func(error) interface{})
+		}
+		c.guards[typ.Elem()] = guard
+	})
+}
+
+// guardField sets the guard of the i-th field of the struct ptr points to,
+// whose bean missing is missing, unless the field is set already.
+func (c *Container) guardField(ptr interface{}, i int, missing string) error {
+	typ := reflect.TypeOf(ptr).Elem()
+	fv := reflect.ValueOf(ptr).Elem().Field(i)
+	if !fv.IsZero() {
+		return nil
+	}
+	unwired := &UnwiredError{Field: typeName(typ) + "." + typ.Field(i).Name, Missing: missing}
+	var guard reflect.Value
+	switch ft := fv.Type(); ft.Kind() {
+	case reflect.Func:
+		guard = reflect.MakeFunc(ft, func([]reflect.Value) []reflect.Value {
+			if ft.NumOut() == 0 || ft.Out(ft.NumOut()-1) != _errorType {
+				panic(unwired)
+			}
+			out := make([]reflect.Value, ft.NumOut())
+			for i := range out {
+				out[i] = reflect.Zero(ft.Out(i))
+			}
+			out[len(out)-1] = reflect.ValueOf(unwired)
+			return out
+		})
+	case reflect.Interface:
+		newGuard, ok := c.guards[ft]
+		if !ok {
+			return nil
+		}
+		impl := newGuard(unwired)
+		if impl == nil || !reflect.TypeOf(impl).Implements(ft) {
+			return fmt.Errorf("guard of %v returned %T", ft, impl)
+		}
+		guard = reflect.ValueOf(impl)
+	default:
+		return nil
+	}
+	fv, err := settable(fv)
+	if err != nil {
+		return err
+	}
+	fv.Set(guard)
+	return nil
+}
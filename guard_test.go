@@ -0,0 +1,50 @@
+package keeper
+
+import (
+	"errors"
+	"testing"
+)
+
+type Mailer interface {
+	Send(to string) error
+}
+
+type mailerGuard struct {
+	err error
+}
+
+func (g mailerGuard) Send(string) error { return g.err }
+
+type signupService struct {
+	mailer  Mailer                  `name:"mailer,optional"`
+	audit   Cache                   `name:"audit,optional"`
+	notify  func(user string) error `name:"notify,optional"`
+	metrics func(n int)             `name:"metrics,optional"`
+}
+
+func TestContainer_GuardMissing(t *testing.T) {
+	unsafeOnly(t)
+	c := New(GuardMissing(), Guard((*Mailer)(nil), func(err error) interface{} { return mailerGuard{err} }))
+	svc := new(signupService)
+	if err := c.Register(svc, Name("signupService")); err != nil {
+		t.Fatal(err)
+	}
+	var unwired *UnwiredError
+	if err := svc.mailer.Send("alice"); !errors.As(err, &unwired) || unwired.Missing != "mailer" {
+		t.Fatalf("got %v", err)
+	}
+	if svc.audit != nil {
+		t.Fatal("interface without guard should be left nil")
+	}
+	err := svc.notify("alice")
+	if err == nil || err.Error() != "keeper: signupService.notify is unwired, bean notify is missing" {
+		t.Fatalf("got %v", err)
+	}
+	defer func() {
+		if p, ok := recover().(*UnwiredError); !ok || p.Field != "signupService.metrics" {
+			t.Fatalf("got %v", p)
+		}
+	}()
+	svc.metrics(1)
+	t.Fatal("guard without error result should panic")
+}
@@ -0,0 +1,11 @@
+package keeper
+
+// HideDependencies is an Option that makes every dependency for which hide
+// returns true unresolvable, as if the bean wasn't registered. Find isn't
+// affected. It lets tests exercise the optional and fallback paths of
+// dependents; keepertest.WithMissing is the convenient form.
+func HideDependencies(hide func(name string) bool) Option {
+	return optionFunc(func(c *Container) {
+		c.hidden = hide
+	})
+}
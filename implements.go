@@ -0,0 +1,81 @@
+package keeper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MustImplement is a RegisterOption that fails the registration of a bean
+// which doesn't implement the interface iface points to, documenting what
+// the bean stands for and catching refactors which break it at once rather
+// than at the injection into a dependent:
+//
+//	c.Register(new(SMTPMailer), keeper.Name("mailer"), keeper.MustImplement((*Mailer)(nil)))
+//
+// Factory beans are checked when built, and beans given to Swap too.
+func MustImplement(iface interface{}) RegisterOption {
+	return registerOptionFunc(func(options *registerOptions) {
+		options.Implements = append(options.Implements, reflect.TypeOf(iface))
+	})
+}
+
+// validateImplements checks the MustImplement options are given pointers to
+// interfaces.
+func (opt registerOptions) validateImplements() error {
+	for _, typ := range opt.Implements {
+		if typ == nil || typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Interface {
+			return fmt.Errorf("invalid MustImplement(%v): must provide pointer to interface", typ)
+		}
+	}
+	return nil
+}
+
+// checkImplements checks bean implements the interfaces of MustImplement.
+func (opt registerOptions) checkImplements(bean interface{}) error {
+	typ := reflect.TypeOf(bean)
+	for _, iface := range opt.Implements {
+		if typ == nil || !typ.Implements(iface.Elem()) {
+			return fmt.Errorf("%s doesn't implement %s: %s", opt.Name, typeName(iface.Elem()), missingMethod(typ, iface.Elem()))
+		}
+	}
+	return nil
+}
+
+// missingMethod describes why typ doesn't implement iface.
+func missingMethod(typ, iface reflect.Type) string {
+	if typ == nil {
+		return "it's nil"
+	}
+	for i := 0; i < iface.NumMethod(); i++ {
+		m := iface.Method(i)
+		impl, ok := typ.MethodByName(m.Name)
+		if !ok {
+			if typ.Kind() != reflect.Ptr && typ.Kind() != reflect.Interface {
+				if _, ok := reflect.PtrTo(typ).MethodByName(m.Name); ok {
+					return fmt.Sprintf("method %s has a pointer receiver, register a *%s", m.Name, typeName(typ))
+				}
+			}
+			return fmt.Sprintf("%s has no method %s", typeName(typ), m.Name)
+		}
+		if impl.Type.NumIn() > 0 && typ.Kind() != reflect.Interface {
+			impl.Type = methodFunc(impl.Type)
+		}
+		if impl.Type != m.Type {
+			return fmt.Sprintf("method %s of %s is %v, want %v", m.Name, typeName(typ), impl.Type, m.Type)
+		}
+	}
+	return "unexported methods differ"
+}
+
+// methodFunc drops the receiver of the func type of a method.
+func methodFunc(typ reflect.Type) reflect.Type {
+	in := make([]reflect.Type, typ.NumIn()-1)
+	for i := range in {
+		in[i] = typ.In(i + 1)
+	}
+	out := make([]reflect.Type, typ.NumOut())
+	for i := range out {
+		out[i] = typ.Out(i)
+	}
+	return reflect.FuncOf(in, out, typ.IsVariadic())
+}
@@ -0,0 +1,67 @@
+package keeper
+
+import (
+	"strings"
+	"testing"
+)
+
+type greeterService interface {
+	Greet() string
+}
+
+type politeGreeter struct{}
+
+func (*politeGreeter) Greet() string { return "good morning" }
+
+type mumblingGreeter struct{}
+
+func (*mumblingGreeter) Greet(loud bool) string { return "mm" }
+
+func TestMustImplement(t *testing.T) {
+	for _, tt := range []struct {
+		bean interface{}
+		want string
+	}{
+		{new(politeGreeter), ""},
+		{politeGreeter{}, "method Greet has a pointer receiver, register a *politeGreeter"},
+		{new(HelloSrv), "*HelloSrv has no method Greet"},
+		{new(mumblingGreeter), "method Greet of *mumblingGreeter is func(bool) string, want func() string"},
+	} {
+		c := New()
+		err := c.Register(tt.bean, Name("greeter"), MustImplement((*greeterService)(nil)))
+		if tt.want == "" {
+			if err != nil {
+				t.Errorf("%T: %v", tt.bean, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), "greeter doesn't implement greeterService: "+tt.want) {
+			t.Errorf("%T: got %v, want %q", tt.bean, err, tt.want)
+		}
+	}
+}
+
+func TestMustImplementFactoryAndSwap(t *testing.T) {
+	c := New()
+	err := c.RegisterFactory(func(Keeper) (interface{}, error) { return new(HelloSrv), nil },
+		Name("greeter"), MustImplement((*greeterService)(nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Find("greeter") != nil {
+		t.Error("the factory bean should fail to build")
+	}
+
+	c = New()
+	c.Register(new(politeGreeter), Name("greeter"), MustImplement((*greeterService)(nil)))
+	if _, err := c.Swap("greeter", new(HelloSrv)); err == nil {
+		t.Error("swap should check the interface")
+	}
+}
+
+func TestMustImplementInvalid(t *testing.T) {
+	err := New().Register(new(politeGreeter), Name("greeter"), MustImplement(politeGreeter{}))
+	if err == nil || !strings.Contains(err.Error(), "must provide pointer to interface") {
+		t.Errorf("got %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package keeper
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FallibleInitializer is an Initializer whose initialization may fail, like
+// opening a connection. The failure fails Register, or Start for the beans
+// registered with InitOnStart, as an *InitError.
+type FallibleInitializer interface {
+	AfterPropertySet() error
+}
+
+// InitError is the failure of AfterPropertySet of a FallibleInitializer,
+// with the wiring context of the bean:
+//
+//	bean "orderRepo" (*OrderRepo) failed to initialize: dial tcp: connection refused (registered at main.go:42; unresolved optional: Cache <- redis)
+type InitError struct {
+	Bean       string
+	Type       string
+	Site       string   // file:line of the registration of Bean
+	Unresolved []string // the optional fields left zero, as "Field <- dependency"
+	Err        error
+}
+
+func (e *InitError) Error() string {
+	msg := fmt.Sprintf("bean %q (%s) failed to initialize: %v", e.Bean, e.Type, e.Err)
+	var details []string
+	if e.Site != "" {
+		details = append(details, "registered at "+e.Site)
+	}
+	if len(e.Unresolved) > 0 {
+		details = append(details, "unresolved optional: "+strings.Join(e.Unresolved, ", "))
+	}
+	if len(details) > 0 {
+		msg += " (" + strings.Join(details, "; ") + ")"
+	}
+	return msg
+}
+
+func (e *InitError) Unwrap() error { return e.Err }
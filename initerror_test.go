@@ -0,0 +1,51 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var errUnreachable = errors.New("connection refused")
+
+type failingRepo struct {
+	cache *HelloSrv `name:"cache,optional"`
+}
+
+func (r *failingRepo) AfterPropertySet() error { return errUnreachable }
+
+func TestInitError(t *testing.T) {
+	c := New()
+	err := c.Register(&failingRepo{}, Name("repo"))
+	var ierr *InitError
+	if !errors.As(err, &ierr) {
+		t.Fatalf("got %v, want an *InitError", err)
+	}
+	if !errors.Is(err, errUnreachable) {
+		t.Error("the InitError doesn't wrap the failure of AfterPropertySet")
+	}
+	if ierr.Bean != "repo" || ierr.Type != "*failingRepo" || ierr.Site == "" {
+		t.Errorf("got %+v", ierr)
+	}
+	if len(ierr.Unresolved) != 1 || ierr.Unresolved[0] != "cache <- cache" {
+		t.Errorf("Unresolved = %q", ierr.Unresolved)
+	}
+	if strings.Count(err.Error(), "registered at") != 1 {
+		t.Errorf("the site isn't named once: %v", err)
+	}
+	if c.Find("repo") != nil {
+		t.Error("the failed bean was registered")
+	}
+}
+
+func TestInitError_OnStart(t *testing.T) {
+	c := New()
+	if err := c.Register(&failingRepo{}, Name("repo"), InitOnStart()); err != nil {
+		t.Fatal(err)
+	}
+	var ierr *InitError
+	if err := c.Start(context.Background()); !errors.As(err, &ierr) || ierr.Bean != "repo" {
+		t.Fatalf("Start = %v, want the InitError of repo", err)
+	}
+}
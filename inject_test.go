@@ -0,0 +1,85 @@
+package keeper
+
+import (
+	"strings"
+	"testing"
+)
+
+type sequencedCtl struct {
+	helloSrv *HelloSrv `name:"helloService"`
+	tracer   *HelloSrv `name:"tracer,optional"`
+	backup   *HelloSrv `name:"backupService"`
+	events   []string
+}
+
+func (ctl *sequencedCtl) BeforeInject(field string) {
+	ctl.events = append(ctl.events, "before "+field)
+}
+
+func (ctl *sequencedCtl) AfterInject(field string) {
+	ctl.events = append(ctl.events, "after "+field)
+}
+
+func (ctl *sequencedCtl) AfterPropertySet() {
+	ctl.events = append(ctl.events, "init")
+}
+
+func TestContainer_InjectHooks(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	c.Register(new(HelloSrv), Name("helloService"))
+	c.Register(new(HelloSrv), Name("backupService"))
+	ctl := new(sequencedCtl)
+	if err := c.Register(ctl, Name("ctl")); err != nil {
+		t.Fatal(err)
+	}
+	want := "before helloSrv,after helloSrv,before tracer,before backup,after backup,init"
+	if got := strings.Join(ctl.events, ","); got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+type vendoredColumn struct {
+	Opaque
+	id string `name:"id"` // not a dependency
+}
+
+func TestContainer_RegisterOpaque(t *testing.T) {
+	c := New()
+	col := &vendoredColumn{id: "pk"}
+	if err := c.Register(col, Name("column")); err != nil {
+		t.Fatal(err)
+	}
+	if col.id != "pk" {
+		t.Fatal("fields of opaque beans should be left as is")
+	}
+	if err := c.Verify(new(vendoredColumn)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type conventionalCtl struct {
+	helloSrv *HelloSrv
+	port     *HelloSrv // no such bean
+	word     string    // the bean doesn't fit
+}
+
+func TestContainer_InjectByFieldName(t *testing.T) {
+	unsafeOnly(t)
+	c := New(InjectByFieldName())
+	c.Register(&HelloSrv{word: "hello"}, Name("helloSrv"))
+	c.Register(&HelloSrv{}, Name("word"))
+	ctl := new(conventionalCtl)
+	if err := c.Register(ctl, Name("ctl")); err != nil {
+		t.Fatal(err)
+	}
+	if ctl.helloSrv == nil || ctl.helloSrv.word != "hello" || ctl.port != nil || ctl.word != "" {
+		t.Fatalf("got %+v", ctl)
+	}
+
+	ctl = new(conventionalCtl)
+	New().Register(ctl, Name("ctl"))
+	if ctl.helloSrv != nil {
+		t.Fatal("untagged fields should only be injected with InjectByFieldName")
+	}
+}
@@ -0,0 +1,42 @@
+package keeper
+
+import "fmt"
+
+// Invariant is an Option that checks a property of the whole graph spanning
+// several beans, like a single primary database or the consumers of a cache
+// all getting its metrics too, on Build, and so on Start, and on Seal. check
+// is given the View of the container, and its error fails the call.
+//
+//	keeper.Invariant("one primary db", func(view keeper.Keeper) error {
+//		primaries := 0
+//		for _, info := range view.Beans() {
+//			if info.Labels["role"] == "primary" {
+//				primaries++
+//			}
+//		}
+//		if primaries != 1 {
+//			return fmt.Errorf("%d primary dbs", primaries)
+//		}
+//		return nil
+//	})
+func Invariant(name string, check func(view Keeper) error) Option {
+	return optionFunc(func(c *Container) {
+		c.invariants = append(c.invariants, invariant{name: name, check: check})
+	})
+}
+
+type invariant struct {
+	name  string
+	check func(view Keeper) error
+}
+
+// checkInvariants runs every invariant, and returns the violated ones.
+func (c *Container) checkInvariants() error {
+	var errs multiError
+	for _, inv := range c.invariants {
+		if err := inv.check(c.View()); err != nil {
+			errs = append(errs, fmt.Errorf("invariant %s is violated: %v", inv.name, err))
+		}
+	}
+	return errs.errOrNil()
+}
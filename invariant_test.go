@@ -0,0 +1,60 @@
+package keeper
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func onePrimary(view Keeper) error {
+	primaries := 0
+	for _, info := range view.Beans() {
+		if info.Labels["role"] == "primary" {
+			primaries++
+		}
+	}
+	if primaries != 1 {
+		return fmt.Errorf("%d primary dbs", primaries)
+	}
+	return nil
+}
+
+func TestInvariant(t *testing.T) {
+	c := New(Invariant("one primary db", onePrimary), Invariant("view only", func(view Keeper) error {
+		if err := view.Register(new(HelloSrv), Name("sneaky")); !errors.Is(err, ErrReadOnly) {
+			return fmt.Errorf("got %v", err)
+		}
+		return nil
+	}))
+	c.Register(new(HelloSrv), Name("db1"), Label("role", "primary"))
+	c.Register(new(HelloSrv), Name("db2"), Label("role", "primary"))
+	err := c.Build()
+	if err == nil || err.Error() != "invariant one primary db is violated: 2 primary dbs" {
+		t.Fatalf("got %v", err)
+	}
+	if err := c.Seal(); err == nil {
+		t.Fatal("Seal should check the invariants")
+	}
+	if err := c.Reset(); err != nil {
+		t.Fatalf("container should be left unsealed, got %v", err)
+	}
+
+	c.Register(new(HelloSrv), Name("db1"), Label("role", "primary"))
+	c.Register(new(HelloSrv), Name("db2"), Label("role", "replica"))
+	if err := c.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Seal(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInvariantSkippedOnWiringErrors(t *testing.T) {
+	c := New(OnMissing(MissingDefer), Invariant("never", func(Keeper) error { return errors.New("checked") }))
+	c.Register(new(HelloCtl), Name("helloCtl"))
+	err := c.Build()
+	if err == nil || strings.Contains(err.Error(), "checked") {
+		t.Fatalf("got %v, want only the wiring errors", err)
+	}
+}
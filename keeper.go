@@ -1,16 +1,28 @@
 package keeper
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"reflect"
+	"regexp"
 	"strings"
-	"unsafe"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const (
 	_nameTag     = "name"
+	_valueTag    = "value"
+	_ctxTag      = "ctx"
+	_configTag   = "config"
 	_optionalTag = "optional"
+
+	_defaultOption   = "default"   // bean injected when the named one is missing, or the default config value
+	_ifProfileOption = "ifprofile" // inject only when one of the '|' separated profiles is active
 )
 
 // A batch of initial action to be invoked after bean`s reference injected.
@@ -18,8 +30,37 @@ type Initializer interface {
 	AfterPropertySet()
 }
 
-// Option configures a Container. It's included for future functionality;
-// currently, there are no concrete implementations.
+// BeforeInjector is implemented by beans which need to know when the tagged
+// field is about to be injected. Fields are injected in declaration order.
+type BeforeInjector interface {
+	BeforeInject(field string)
+}
+
+// AfterInjector is implemented by beans which react as each dependency
+// arrives, AfterInject is invoked once the field is set; a skipped optional
+// field isn't reported.
+type AfterInjector interface {
+	AfterInject(field string)
+}
+
+// NoInjector is implemented by types whose fields must never be injected,
+// like vendored types using the name tag for something else: they are
+// registered without scanning their fields. Embedding Opaque implements it.
+type NoInjector interface {
+	NoInject()
+}
+
+// Opaque is embedded by the types opting out of injection:
+//
+//	type Column struct {
+//		keeper.Opaque
+//		name string `name:"id"` // an ORM tag
+//	}
+type Opaque struct{}
+
+func (Opaque) NoInject() {}
+
+// Option configures a Container.
 type Option interface {
 	applyOption(*Container)
 }
@@ -32,7 +73,36 @@ var noopRegisterOption registerOptions
 
 // options for bean register
 type registerOptions struct {
-	Name string
+	Name        string
+	Description string
+	Labels      map[string]string
+	TTL         time.Duration
+	ServeStale  bool     // the expired factory bean when its rebuild fails
+	Requires    []string // capabilities a dependent must hold to get the bean injected
+	Grants      []string // capabilities the bean holds
+	Implements  []reflect.Type
+	ReadOnly    bool
+	InitOnStart bool // AfterPropertySet is invoked by Start
+	NoInit      bool // AfterPropertySet is never invoked
+	Elements    bool // the elements of the collection bean are loaded
+	Group       string
+	Weight      int
+	Phases      map[string][]func(context.Context) error // by phase name
+	StartTasks  []func(context.Context) error            // run concurrently by Start
+	Middleware  []middlewareFunc                         // contributed to the Chain of their type
+	WaitFor     []readiness                              // external resources waited for before loading
+	Flag        string                                   // the bean serves its name while the flag is enabled
+	Otherwise   interface{}                              // serving the name while Flag is disabled
+	adopted     bool                                     // registered by Adopt, never loaded
+	site        string                                   // file:line of the registration
+}
+
+// loadable reports whether the bean registered with the options gets its
+// dependencies injected.
+func (opt registerOptions) loadable(bean interface{}) bool {
+	_, lazy := bean.(*lazyBean)
+	typ := reflect.TypeOf(bean)
+	return (typ == nil || typ.Kind() == reflect.Ptr) && !opt.adopted && !opt.ReadOnly && !lazy // load rejects nil
 }
 
 func (opt registerOptions) Validate() error {
@@ -42,7 +112,13 @@ func (opt registerOptions) Validate() error {
 	if strings.ContainsRune(opt.Name, '`') {
 		return fmt.Errorf("invalid Name(%q): names cannot contain backquotes", opt.Name)
 	}
-	return nil
+	if opt.Group == opt.Name {
+		return fmt.Errorf("invalid Group(%q): the bean has the name", opt.Group)
+	}
+	if err := opt.validateFlag(); err != nil {
+		return err
+	}
+	return opt.validateImplements()
 }
 
 // A RegisterOption modifies the default behavior of Register.
@@ -59,13 +135,13 @@ func (f registerOptionFunc) applyRegisterOption(opts *registerOptions) { f(opts)
 //
 // Given,
 //
-//   type Connection struct {}
+//	type Connection struct {}
 //
 // The following will provide two connections to the container: one under the
 // name "ro" and the other under the name "rw".
 //
-//   c.Register(new(Connection), keeper.Name("ro"))
-//   c.Register(new(Connection), keeper.Name("rw"))
+//	c.Register(new(Connection), keeper.Name("ro"))
+//	c.Register(new(Connection), keeper.Name("rw"))
 //
 // This option cannot be provided for constructors which produce result
 // objects.
@@ -75,47 +151,271 @@ func Name(name string) RegisterOption {
 	})
 }
 
+// Description is a RegisterOption that documents what the bean is for, it's
+// reported by Beans, the debug handler and the DOT export.
+//
+//	c.Register(pool, keeper.Name("ro"), keeper.Description("read-only MySQL pool"))
+func Description(text string) RegisterOption {
+	return registerOptionFunc(func(options *registerOptions) {
+		options.Description = text
+	})
+}
+
+// Label is a RegisterOption that attaches a key/value label to the bean, like
+// its tier or owner. Labels are reported by Beans and compared by Diff.
+func Label(key, value string) RegisterOption {
+	return registerOptionFunc(func(options *registerOptions) {
+		if options.Labels == nil {
+			options.Labels = make(map[string]string)
+		}
+		options.Labels[key] = value
+	})
+}
+
 type Keeper interface {
 	// find the bean of the name
 	Find(name string) interface{}
 	// get copy of all bean map
 	All() map[string]interface{}
+	// find the beans whose names match a glob pattern
+	FindMatching(pattern string) (map[string]interface{}, error)
+	// find the beans whose names match a regular expression
+	FindRegexp(re *regexp.Regexp) map[string]interface{}
 	// inject of node`s dependence, but not register
 	Provider(ptr interface{}) error
 	// reject the dependence and register it
 	Register(ptr interface{}, opts ...RegisterOption) error
+	// start every Starter bean in registration order
+	Start(ctx context.Context) error
+	// run every Migrator bean in dependency order
+	Migrate(ctx context.Context) error
+	// stop workers started by Start and dispose beans
+	Close() error
+	// describe all beans in registration order
+	Beans() []BeanInfo
+	// register an already initialized bean as is, without injecting it
+	Adopt(name string, bean interface{}, opts ...RegisterOption) error
+	// register several beans in order
+	RegisterBatch(regs ...Registration) error
+	// register the beans of the variants selected by the profiles
+	RegisterVariants(variants ...Variant) error
+	// resolve the dependencies deferred by MissingDefer
+	Build() error
+	// build only the beans the roots depend on
+	BuildFor(roots ...string) error
+	// check the deferred dependencies and the wiring of beans without injecting
+	Verify(beans ...interface{}) error
+	// register a factory building the bean of a type
+	RegisterTyped(typ reflect.Type, factory FactoryFunc) error
+	// resolve the bean of a type
+	ResolveType(typ reflect.Type) (interface{}, error)
+	// register a factory building the bean on first use
+	RegisterFactory(factory FactoryFunc, opts ...RegisterOption) error
+	// inject of node`s dependence and values of ctx, but not register
+	ProvideContext(ctx context.Context, ptr interface{}) error
+	// replace a bean and the references its dependents hold
+	Swap(name string, bean interface{}) (interface{}, error)
+	// register the fallback implementation of an interface
+	RegisterDefaultFor(iface interface{}, impl interface{}) error
+	// diagnose common problems of the live graph
+	Doctor() []Finding
+	// write the state of the beans for crash diagnostics
+	DumpState(w io.Writer) error
+	// report the cost of building each bean
+	Stats() []BuildStats
+	// install the beans of modules
+	Install(modules ...Module) error
+	// install the module exported by a Go plugin
+	LoadPlugin(path string) error
+	// import the beans of another container
+	Merge(other Keeper, policy ConflictPolicy) error
+	// run the funcs subscribed to a lifecycle phase
+	RunPhase(ctx context.Context, phase string) error
+	// count the outcomes of injecting each field
+	FieldStats() []FieldStats
+	// report the runs of the scheduled beans
+	Jobs() []JobStats
+	// describe the graph in the portable Spec format
+	ExportSpec() Spec
+	// iterate the beans without copying them
+	Range(fn func(name string, bean interface{}) bool)
+	// find the beans whose names start with prefix
+	AllWithPrefix(prefix string) map[string]interface{}
+	// find the beans registered with a label
+	AllLabeled(key, value string) map[string]interface{}
+	// the context-first interface of the container
+	V2() KeeperV2
+	// register a dig style constructor as the factory of its results
+	ProvideConstructor(ctor interface{}) error
+	// the read-only view of the container, for plugins
+	View() Keeper
+	// list the injections into the beans for reviews
+	AuditLog() string
+	// check the beans have the expected types
+	AssertTypes(expected map[string]reflect.Type) error
+	// build factory beans ahead of traffic
+	Warm(ctx context.Context, names ...string) error
+	// hash the wiring of the graph to detect drift between instances
+	Fingerprint() string
+	// check the invariants and forbid Reset
+	Seal() error
+	// dispose and clear all beans
+	Reset() error
 }
 
 func New(opts ...Option) Keeper {
 	c := &Container{
-		nodes: make(map[string]interface{}),
+		deps:     make(map[string][]string),
+		opts:     make(map[string]registerOptions),
+		typed:    make(map[reflect.Type]*lazyBean),
+		defaults: make(map[reflect.Type]interface{}),
+		logger:   nopLogger{},
+		tracer:   nopTracer{},
+
+		restartPolicy: DefaultRestartPolicy,
 	}
+	c.nodes.Store(make(map[string]interface{}))
 	for _, opt := range opts {
 		opt.applyOption(c)
 	}
+	if c.vars != nil {
+		c.publishVars()
+	}
+	if c.rand != nil {
+		c.seedBalancers()
+	}
+	c.registerBuiltins()
 	return c
 }
 
 // Container defines the behavior of the manager for members and their dependencies.
 // Container is an application level global context, in most cases, only one take effect in the app.
 type Container struct {
-	nodes map[string]interface{}
+	mu       sync.RWMutex // serializes writers, never held while calling into beans
+	nodes    atomic.Value // map[string]interface{}, immutable once published
+	instance string       // name given to NewNamed
+
+	order     []string            // bean names in registration order
+	deps      map[string][]string // bean names injected into each bean
+	typed     map[reflect.Type]*lazyBean
+	defaults  map[reflect.Type]interface{} // fallback implementation per interface
+	profiles  map[string]bool
+	balancers map[string]Balancer // of groups, by group name
+	opts      map[string]registerOptions
+	logger    Logger
+	slog      *slog.Logger // injected into the beans by WithSlog
+	tracer    Tracer
+	hidden    func(name string) bool        // dependencies treated as missing
+	parent    func(name string) interface{} // resolves the names the container lacks
+	children  map[string]*Container         // by name given to ChildOf
+
+	missingPolicy MissingPolicy
+	deferred      []deferredField // dependencies to retry on Build
+	injected      []injectedField // fields of registered beans which got a bean injected
+	initPending   []string        // beans registered with InitOnStart, initialized by Start
+	loading       map[string]bool // beans being registered, whose initializers may register others
+	fillOptional  bool
+	optional      []optionalField // missing optional dependencies to fill on registration
+	started       bool
+	closed        bool
+	sealed        bool
+
+	guardMissing bool
+	guards       map[reflect.Type]func(error) interface{} // by interface, for GuardMissing
+	nilObjects   map[reflect.Type]interface{}             // by interface, set by NilObjectFor
+	invariants   []invariant
+
+	lastErrors   map[string]string
+	failed       []string // beans with an error, in the order of their first one
+	stateEncoder StateEncoder
+	panicDump    io.Writer
+	dumped       int32 // the first panic was dumped
+
+	accounting bool
+	stats      map[string]BuildStats
+	buildHooks []func(BuildStats)
+
+	injectHooks  []func(Injection)
+	destroyHooks []func(name string)
+	warmHooks    []func(WarmProgress)
+
+	noCallSites     bool
+	fieldNames      bool
+	dev             bool
+	trackFields     bool
+	fieldStats      map[fieldKey]*FieldStats
+	bestEffort      bool
+	allowConversion bool
+	ordering        Ordering
+	strictTypes     bool
+	deniedTypes     []deniedType
+	config          ConfigSource
+	values          []valueBinding // value tagged fields, re-resolved when the config changes
+	configRoot      interface{}
+
+	flags FlagSource
+	gates map[string]*flagGate // beans registered with IfFlag, by name
+
+	vars      *containerVars // published by ExpVars
+	buildInfo *BuildInfo     // registered by WithBuildInfo
+	rand      *seededRand    // set by WithSeed
+
+	restartPolicy RestartPolicy
+	jobs          map[string]*JobStats
+	stopWorkers   context.CancelFunc
+	workers       sync.WaitGroup
+}
+
+// published returns the current bean map, which must not be modified.
+// Readers never lock, writers copy the map and publish the copy under mu.
+func (c *Container) published() map[string]interface{} {
+	return c.nodes.Load().(map[string]interface{})
 }
 
 func (c *Container) Find(name string) interface{} {
-	return c.nodes[name]
+	return c.find(nil, name)
 }
 
-func (c *Container) All() map[string]interface{} {
-	cm := make(map[string]interface{}, len(c.nodes))
-	for name, bean := range c.nodes {
-		cm[name] = bean
+// find is Find within scope, when a factory resolves the bean of name.
+func (c *Container) find(scope *buildScope, name string) interface{} {
+	bean := c.lookupIn(scope, name)
+	if bean == nil && c.dev {
+		c.devPanic(fmt.Sprintf("Find(%q) found nothing", name), c.suggest(name))
+	}
+	return bean
+}
+
+// lookup finds the bean of name, building factory beans and picking a
+// member of groups, then asks the parent.
+func (c *Container) lookup(name string) interface{} {
+	return c.lookupIn(nil, name)
+}
+
+// lookupIn is lookup within scope, when a factory resolves the bean of name.
+func (c *Container) lookupIn(scope *buildScope, name string) interface{} {
+	bean := c.published()[name]
+	switch b := bean.(type) {
+	case *lazyBean:
+		bean, _ = c.buildErr(scope, name, b)
+	case *beanGroup:
+		bean = c.pick(scope, b)
+	case nil:
+		if c.parent != nil {
+			bean = c.parent(name)
+		}
 	}
-	return cm
+	c.countLookup(bean)
+	return bean
+}
+
+// All builds the factory beans which aren't built yet.
+func (c *Container) All() map[string]interface{} {
+	return c.findNames(func(string) bool { return true })
 }
 
 func (c *Container) Provider(ptr interface{}) error {
-	return c.load(ptr, noopRegisterOption)
+	_, err := c.load(context.Background(), ptr, noopRegisterOption)
+	return err
 }
 
 func (c *Container) Register(node interface{}, opts ...RegisterOption) error {
@@ -126,57 +426,435 @@ func (c *Container) Register(node interface{}, opts ...RegisterOption) error {
 	if err := options.Validate(); err != nil {
 		return err
 	}
-	_, exist := c.nodes[options.Name]
-	if exist {
-		return fmt.Errorf("register duplicate! %s already register by %s", options.Name, reflect.TypeOf(node).Name())
+	return c.register(node, options)
+}
+
+// Adopt registers bean, typically a legacy global singleton, under name
+// without loading it: its tags are ignored and AfterPropertySet isn't invoked.
+// Adopted beans are marked in Beans and the DOT export, which gives teams
+// migrating off global variables a low-risk way to put them in the graph.
+func (c *Container) Adopt(name string, bean interface{}, opts ...RegisterOption) error {
+	var options registerOptions
+	for _, o := range opts {
+		o.applyRegisterOption(&options)
+	}
+	options.Name = name
+	options.adopted = true
+	if err := options.Validate(); err != nil {
+		return err
+	}
+	if bean == nil {
+		return fmt.Errorf("cannot adopt nil as %s", name)
+	}
+	return c.register(bean, options)
+}
+
+func (c *Container) register(node interface{}, options registerOptions) error {
+	return c.registerContext(context.Background(), node, options)
+}
+
+// registerContext is register with the ctx of the traces and ctx tagged fields.
+func (c *Container) registerContext(ctx context.Context, node interface{}, options registerOptions) error {
+	defer c.dumpOnPanic(options.Name)
+	if !c.noCallSites {
+		options.site = callSite()
+	}
+	err := c.traced(ctx, "keeper.Register", options.Name, func(ctx context.Context) error {
+		return c.registerTraced(ctx, node, options)
+	})
+	if err != nil {
+		var ierr *InitError
+		if options.site != "" && !errors.As(err, &ierr) { // which names the site already
+			err = fmt.Errorf("%w (registered at %s)", err, options.site)
+		}
+		c.recordError(options.Name, err)
+	}
+	c.countRegistration(err)
+	if err == nil && options.Flag != "" {
+		err = c.gate(node, options)
+	}
+	return err
+}
+
+func (c *Container) registerTraced(ctx context.Context, node interface{}, options registerOptions) error {
+	if _, exist := c.published()[options.Name]; exist {
+		return c.duplicate(node, options.Name)
+	}
+	if _, lazy := node.(*lazyBean); !lazy {
+		if err := options.checkImplements(node); err != nil {
+			return err
+		}
+	}
+	if err := c.beginLoading(options.Name); err != nil {
+		return err
+	}
+	defer c.endLoading(options.Name)
+	if err := c.await(ctx, options); err != nil {
+		return err
 	}
-	if reflect.TypeOf(node).Kind() == reflect.Ptr { // ptr needs to inject dependence
-		err := c.load(node, options)
+	var w wiring
+	if options.Elements && !options.ReadOnly && !options.adopted {
+		err := c.measure(options.Name, func() (err error) {
+			w, err = c.loadElements(ctx, node, options)
+			return err
+		})
 		if err != nil {
 			return err
 		}
+	} else if options.loadable(node) { // ptr needs to inject dependence
+		err := c.measure(options.Name, func() (err error) {
+			w, err = c.load(ctx, node, options)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	initNow, err := c.commit(node, options, w)
+	if err != nil {
+		return err
+	}
+	if initNow { // started while loading
+		if _, err := c.initialize(ctx, node, options, w.unresolved); err != nil {
+			return err
+		}
 	}
-	c.nodes[options.Name] = node // normal node
+	c.fill(ctx, options.Name)
 	return nil
 }
 
-// not thread safe
-func (c *Container) load(ptr interface{}, _ registerOptions) error {
+// duplicate is the error of registering node under the name of a registered bean.
+func (c *Container) duplicate(node interface{}, name string) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.duplicateLocked(node, name)
+}
+
+func (c *Container) duplicateLocked(node interface{}, name string) error {
+	err := fmt.Sprintf("register duplicate! %s already register by %s", name, reflect.TypeOf(node).Name())
+	if site := c.opts[name].site; site != "" {
+		err += " at " + site
+	}
+	return errors.New(err)
+}
+
+// commit publishes the bean loaded with w, and reports whether its
+// initialization left to Start is due already.
+func (c *Container) commit(node interface{}, options registerOptions, w wiring) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	nodes := c.published()
+	if _, exist := nodes[options.Name]; exist { // registered concurrently while loading
+		return false, c.duplicateLocked(node, options.Name)
+	}
+	next := make(map[string]interface{}, len(nodes)+1)
+	for name, bean := range nodes {
+		next[name] = bean
+	}
+	next[options.Name] = node // normal node
+	if options.Group != "" {
+		g, err := c.join(nodes, options)
+		if err != nil {
+			return false, err
+		}
+		next[options.Group] = g
+	}
+	c.nodes.Store(next)
+	c.order = append(c.order, options.Name)
+	c.opts[options.Name] = options
+	c.deps[options.Name] = w.deps
+	c.values = append(c.values, w.values...)
+	c.deferred = append(c.deferred, w.deferred...)
+	c.injected = append(c.injected, w.fields...)
+	c.optional = append(c.optional, w.optional...)
+	if w.initOnStart && !c.started {
+		c.initPending = append(c.initPending, options.Name)
+		return false, nil
+	}
+	return w.initOnStart, nil
+}
+
+// wiring records what load injected into a bean.
+type wiring struct {
+	deps     []string
+	values   []valueBinding
+	deferred []deferredField
+	fields   []injectedField
+	optional []optionalField // missing optional dependencies, kept by FillOptional
+
+	unresolved []string // optional fields left zero, as "Field <- dependency"
+
+	initOnStart bool // AfterPropertySet is left to Start
+}
+
+// injectedField is a field which got a bean injected, updated by Swap.
+type injectedField struct {
+	bean   string // the dependent, empty when loaded by Provider
+	ptr    interface{}
+	field  int
+	source string
+}
+
+// load injects the dependencies of ptr. It doesn't hold the lock, so
+// initializers are free to use the container.
+func (c *Container) load(ctx context.Context, ptr interface{}, options registerOptions) (w wiring, err error) {
+	err = c.traced(ctx, "keeper.load", options.Name, func(ctx context.Context) error {
+		w, err = c.loadTraced(ctx, ptr, options)
+		return err
+	})
+	return w, err
+}
+
+func (c *Container) loadTraced(ctx context.Context, ptr interface{}, options registerOptions) (wiring, error) {
+	var w wiring
 	typ := reflect.TypeOf(ptr)
 	if typ == nil {
-		return errors.New("can't provide an untyped nil")
+		return w, errors.New("can't provide an untyped nil")
 	}
 	if typ.Kind() != reflect.Ptr {
-		return fmt.Errorf("must provide pointer of bean, got %v (type %v)", ptr, typ)
+		return w, fmt.Errorf("must provide pointer of bean, got %v (type %v)", ptr, typ)
 	}
 	typ = typ.Elem()
-	val := reflect.ValueOf(ptr).Elem()
-	for i := 0; i < typ.NumField(); i++ {
-		fv := val.Field(i)
-		tv := typ.Field(i)
-		tag, ok := tv.Tag.Lookup(_nameTag)
-		if !ok {
+	if _, opaque := ptr.(NoInjector); opaque || typ.Kind() != reflect.Struct { // channels, funcs, etc. have no fields to inject
+		initialized, err := c.initialize(ctx, ptr, options, nil)
+		w.initOnStart = !initialized
+		return w, err
+	}
+	before, _ := ptr.(BeforeInjector)
+	after, _ := ptr.(AfterInjector)
+	for i, tv := range planOf(typ).fields { // fields are always injected in declaration order
+		switch tv.class {
+		case factoryFieldClass:
+			if err := c.setFactory(ptr, i); err != nil {
+				return w, err
+			}
 			continue
+		case chainFieldClass:
+			if err := c.setChain(ptr, i, &w); err != nil {
+				return w, err
+			}
+			continue
+		case lazyFieldClass:
+			tag := tv.tag
+			if tv.tagKey != _nameTag {
+				tag = tv.Name
+			}
+			if err := c.setLazy(ptr, i, tag, options); err != nil {
+				return w, err
+			}
+			continue
+		case loggerField:
+			if c.slog != nil {
+				if err := c.setSlog(ptr, i, options.Name); err != nil {
+					return w, err
+				}
+				continue
+			}
+			if !c.byFieldName(tv.StructField) {
+				continue
+			}
+		case untaggedField:
+			if !c.byFieldName(tv.StructField) {
+				continue
+			}
 		}
-		depOpts := strings.Split(tag, ",")
-		name := depOpts[0]
-		var optional bool
-		if len(depOpts) > 1 && depOpts[1] == _optionalTag {
-			optional = true
+		if before != nil {
+			before.BeforeInject(tv.Name)
 		}
-		elem := c.Find(name)
-		if elem == nil {
-			if optional {
-				continue
+		injected, err := c.loadField(ctx, ptr, i, options, c.missingPolicy, &w)
+		if err != nil {
+			return w, err
+		}
+		if injected && after != nil {
+			after.AfterInject(tv.Name)
+		}
+	}
+	initialized, err := c.initialize(ctx, ptr, options, w.unresolved)
+	w.initOnStart = !initialized
+	return w, err
+}
+
+// initialize invokes AfterPropertySet of an Initializer bean, unless it's
+// registered with InitOnStart before Start, and reports whether it did.
+// The failure of a FallibleInitializer is returned as an *InitError, with
+// the unresolved optional fields of the bean.
+func (c *Container) initialize(ctx context.Context, ptr interface{}, options registerOptions, unresolved []string) (bool, error) {
+	var afterPropertySet func() error
+	switch initializer := ptr.(type) {
+	case Initializer:
+		afterPropertySet = func() error {
+			initializer.AfterPropertySet()
+			return nil
+		}
+	case FallibleInitializer:
+		afterPropertySet = initializer.AfterPropertySet
+	}
+	if afterPropertySet == nil || options.NoInit {
+		return true, nil
+	}
+	if options.InitOnStart {
+		c.mu.RLock()
+		started := c.started
+		c.mu.RUnlock()
+		if !started {
+			return false, nil
+		}
+	}
+	err := c.traced(ctx, "keeper.AfterPropertySet", options.Name, func(context.Context) error {
+		return afterPropertySet()
+	})
+	if err != nil {
+		return true, &InitError{
+			Bean:       options.Name,
+			Type:       typeName(reflect.TypeOf(ptr)),
+			Site:       options.site,
+			Unresolved: unresolved,
+			Err:        err,
+		}
+	}
+	return true, nil
+}
+
+// loadField injects the i-th field of the struct ptr points to, and reports
+// whether the field was set.
+func (c *Container) loadField(ctx context.Context, ptr interface{}, i int, options registerOptions, policy MissingPolicy, w *wiring) (injected bool, err error) {
+	typ := reflect.TypeOf(ptr).Elem()
+	tv := planOf(typ).fields[i]
+	switch tv.tagKey {
+	case _valueTag:
+		return c.loadValue(ptr, options.Name, i, tv.tag, w)
+	case _ctxTag:
+		return loadContext(ctx, ptr, i, tv.tag)
+	case _configTag:
+		return c.loadConfig(ptr, i, tv.tag)
+	}
+	var spec tagSpec
+	inactive := false
+	if c.trackFields {
+		defer func() {
+			if !inactive {
+				c.recordField(typ, tv.Name, spec.flag(_optionalTag), injected, err)
 			}
-			return fmt.Errorf("failed to load %s", name)
+		}()
+	}
+	tag := tv.tag
+	if tv.tagKey == "" { // resolved by its field name
+		tag = tv.Name
+	}
+	if spec, err = parseTag(tag); err != nil {
+		return false, fmt.Errorf("failed to load %s.%s: %v", typeName(typ), tv.Name, err)
+	}
+	if spec, err = c.expandSpec(spec); err != nil {
+		return false, fmt.Errorf("failed to load %s.%s: %v", typeName(typ), tv.Name, err)
+	}
+	name := spec.name
+	if !c.profileActive(spec) { // the default bean, if any, stands in
+		fallback, ok := spec.option(_defaultOption)
+		if !ok {
+			inactive = true
+			return false, nil
 		}
-		fv = reflect.NewAt(fv.Type(), unsafe.Pointer(fv.UnsafeAddr())).Elem()
-		nv := reflect.ValueOf(elem).Elem()
-		fv.Set(nv)
+		name = fallback
 	}
-	if initializer, ok := ptr.(Initializer); ok {
-		initializer.AfterPropertySet()
+	if name == options.Name && name != "" {
+		return true, c.loadSelf(ptr, i, name)
 	}
-	return nil
+	wanted := name
+	elem, name, path := c.resolvePath(name)
+	fallback, hasFallback := spec.option(_defaultOption)
+	if elem == nil && hasFallback {
+		elem, name, path = c.resolvePath(fallback)
+	}
+	if elem == nil {
+		if def := c.defaultFor(tv.Type); def != nil {
+			return true, setDefault(ptr, i, def)
+		}
+		if spec.flag(_optionalTag) {
+			w.unresolved = append(w.unresolved, tv.Name+" <- "+name)
+			if c.fillOptional {
+				wants := []string{wanted}
+				if hasFallback && fallback != wanted {
+					wants = append(wants, fallback)
+				}
+				w.optional = append(w.optional, optionalField{deferredField{bean: options.Name, ptr: ptr, field: i, options: options}, wants})
+			}
+			if c.guardMissing {
+				return false, c.guardField(ptr, i, name)
+			}
+			return false, nil
+		}
+		switch policy {
+		case MissingWarn:
+			c.logger.Printf("keeper: %s.%s left zero, %s is missing", typeName(typ), tv.Name, name)
+			return false, nil
+		case MissingDefer:
+			w.deferred = append(w.deferred, deferredField{bean: options.Name, ptr: ptr, field: i, options: options})
+			return false, nil
+		}
+		if c.isLoading(name) {
+			return false, fmt.Errorf("failed to load %s, it's being registered: register %s.%s from Start, or its initializer with InitOnStart", name, typeName(typ), tv.Name)
+		}
+		return false, fmt.Errorf("failed to load %s", name)
+	}
+	if err := c.checkCapability(name, elem, options); err != nil {
+		return false, fmt.Errorf("failed to load %s into %s.%s: %w", name, typeName(typ), tv.Name, err)
+	}
+	fv := reflect.ValueOf(ptr).Elem().Field(i)
+	if path != "" {
+		return c.loadSubValue(ptr, i, elem, name, path, spec, options, w)
+	}
+	nv, err := c.assign(fv.Type(), elem)
+	if err != nil {
+		return false, fmt.Errorf("failed to load %s into %s.%s: %v", name, typeName(typ), tv.Name, err)
+	}
+	if fv, err = settable(fv); err != nil {
+		return false, fmt.Errorf("failed to load %s into %s.%s: %v", name, typeName(typ), tv.Name, err)
+	}
+	if c.dev {
+		c.checkDev(typ, tv.StructField, elem)
+	}
+	fv.Set(nv)
+	if options.Name != "" { // Provider has nothing to record
+		w.deps = append(w.deps, name)
+		w.fields = append(w.fields, injectedField{bean: options.Name, ptr: ptr, field: i, source: name})
+	}
+	c.notifyInject(Injection{Bean: options.Name, Field: tv.Name, Source: name})
+	return true, nil
+}
+
+// resolve finds the bean of name to be injected into a dependent.
+func (c *Container) resolve(name string) interface{} {
+	if c.hidden != nil && c.hidden(name) {
+		return nil
+	}
+	if bean, scoped := c.resolveScoped(name); scoped {
+		return bean
+	}
+	return c.lookup(name)
+}
+
+// namedBean is a bean with the name it is registered under.
+type namedBean struct {
+	name string
+	bean interface{}
+}
+
+// ordered returns a snapshot of the beans in registration order, so callers
+// could invoke the beans without holding the lock. Factory beans which
+// aren't built are left out.
+func (c *Container) ordered() []namedBean {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	nodes := c.published()
+	beans := make([]namedBean, 0, len(c.order))
+	for _, name := range c.order {
+		bean := nodes[name]
+		if lazy, ok := bean.(*lazyBean); ok {
+			if bean = lazy.peek(); bean == nil {
+				continue
+			}
+		}
+		beans = append(beans, namedBean{name: name, bean: bean})
+	}
+	return beans
 }
@@ -6,6 +6,7 @@ import (
 )
 
 func TestContainer_Register(t *testing.T) {
+    unsafeOnly(t)
     c := New()
     if err := c.Register(new(HelloSrv), Name("helloService")); err != nil {
        t.Fatal(err)
@@ -0,0 +1,18 @@
+// Package keepertest provides helpers for testing code wired by keeper.
+package keepertest
+
+import "github.com/tooky0630/keeper"
+
+// WithMissing is an Option that makes the beans of names unresolvable as
+// dependencies, to simulate an environment where they aren't available:
+//
+//	c := keeper.New(keepertest.WithMissing("kafkaProducer"))
+func WithMissing(names ...string) keeper.Option {
+	missing := make(map[string]bool, len(names))
+	for _, name := range names {
+		missing[name] = true
+	}
+	return keeper.HideDependencies(func(name string) bool {
+		return missing[name]
+	})
+}
@@ -0,0 +1,34 @@
+package keepertest
+
+import (
+	"testing"
+
+	"github.com/tooky0630/keeper"
+)
+
+type producer struct{}
+
+type publisher struct {
+	producer *producer `name:"kafkaProducer,optional"`
+}
+
+type strictPublisher struct {
+	producer *producer `name:"kafkaProducer"`
+}
+
+func TestWithMissing(t *testing.T) {
+	c := keeper.New(WithMissing("kafkaProducer"))
+	if err := c.Register(new(producer), keeper.Name("kafkaProducer")); err != nil {
+		t.Fatal(err)
+	}
+	p := new(publisher)
+	if err := c.Register(p, keeper.Name("publisher")); err != nil {
+		t.Fatal(err)
+	}
+	if p.producer != nil {
+		t.Fatal("missing producer should not be injected")
+	}
+	if err := c.Register(new(strictPublisher), keeper.Name("strictPublisher")); err == nil {
+		t.Fatal("expected error for missing required producer")
+	}
+}
@@ -0,0 +1,51 @@
+package keepertest
+
+import (
+	"sync"
+
+	"github.com/tooky0630/keeper"
+)
+
+// Recorder records the injections a container performs, so tests can assert
+// the wiring without poking unexported fields:
+//
+//	rec := keepertest.NewRecorder()
+//	c := keeper.New(rec.Option())
+//	...
+//	rec.InjectionsOf("helloCtl") // map[helloSrv:helloService]
+type Recorder struct {
+	mu         sync.Mutex
+	injections map[string]map[string]string
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{injections: make(map[string]map[string]string)}
+}
+
+// Option returns the Option installing the Recorder into a container.
+func (r *Recorder) Option() keeper.Option {
+	return keeper.OnInject(r.record)
+}
+
+func (r *Recorder) record(in keeper.Injection) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fields, ok := r.injections[in.Bean]
+	if !ok {
+		fields = make(map[string]string)
+		r.injections[in.Bean] = fields
+	}
+	fields[in.Field] = in.Source
+}
+
+// InjectionsOf returns the name of the bean injected into each field of bean.
+func (r *Recorder) InjectionsOf(bean string) map[string]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := make(map[string]string, len(r.injections[bean]))
+	for field, source := range r.injections[bean] {
+		cp[field] = source
+	}
+	return cp
+}
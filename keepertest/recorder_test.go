@@ -0,0 +1,29 @@
+package keepertest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tooky0630/keeper"
+)
+
+type consumer struct {
+	Producer *producer `name:"kafkaProducer"`
+	Backup   *producer `name:"backupProducer,optional"`
+}
+
+func TestRecorder(t *testing.T) {
+	rec := NewRecorder()
+	c := keeper.New(rec.Option())
+	c.Register(new(producer), keeper.Name("kafkaProducer"))
+	if err := c.Register(new(consumer), keeper.Name("consumer")); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"Producer": "kafkaProducer"}
+	if got := rec.InjectionsOf("consumer"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got := rec.InjectionsOf("kafkaProducer"); len(got) != 0 {
+		t.Fatalf("got %v", got)
+	}
+}
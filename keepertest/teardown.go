@@ -0,0 +1,90 @@
+package keepertest
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tooky0630/keeper"
+)
+
+// Teardown records the order Close destroys the beans of a container in,
+// to check every bean outlives its dependents:
+//
+//	td := keepertest.NewTeardown()
+//	c := keeper.New(td.Option())
+//	...
+//	td.Check(t, c)
+type Teardown struct {
+	mu     sync.Mutex
+	order  []string
+	delays map[string]time.Duration
+}
+
+// NewTeardown returns a Teardown which hasn't recorded anything.
+func NewTeardown() *Teardown {
+	return &Teardown{delays: make(map[string]time.Duration)}
+}
+
+// Option returns the Option installing the Teardown into a container.
+func (td *Teardown) Option() keeper.Option {
+	return keeper.OnDestroy(td.record)
+}
+
+// Delay makes the bean of name slow to dispose, Close waits for d before
+// destroying it, to exercise the shutdown timeouts of the application.
+func (td *Teardown) Delay(name string, d time.Duration) *Teardown {
+	td.mu.Lock()
+	defer td.mu.Unlock()
+	td.delays[name] = d
+	return td
+}
+
+func (td *Teardown) record(name string) {
+	td.mu.Lock()
+	td.order = append(td.order, name)
+	delay := td.delays[name]
+	td.mu.Unlock()
+	time.Sleep(delay)
+}
+
+// Order returns the names of the beans destroyed so far, in order.
+func (td *Teardown) Order() []string {
+	td.mu.Lock()
+	defer td.mu.Unlock()
+	return append([]string(nil), td.order...)
+}
+
+// Check closes k, and fails t for each bean destroyed before one of its
+// dependents, and if Close fails.
+func (td *Teardown) Check(t testing.TB, k keeper.Keeper) {
+	t.Helper()
+	if err := k.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+	for _, v := range td.violations(k) {
+		t.Error(v)
+	}
+}
+
+// violations describes the beans destroyed before one of their dependents.
+func (td *Teardown) violations(k keeper.Keeper) []string {
+	destroyed := make(map[string]int)
+	for i, name := range td.Order() {
+		destroyed[name] = i
+	}
+	var violations []string
+	for _, info := range k.Beans() {
+		at, ok := destroyed[info.Name]
+		if !ok {
+			continue
+		}
+		for _, dep := range info.Dependencies {
+			if depAt, ok := destroyed[dep]; ok && depAt < at {
+				violations = append(violations, fmt.Sprintf("%s was destroyed before its dependent %s", dep, info.Name))
+			}
+		}
+	}
+	return violations
+}
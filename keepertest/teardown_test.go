@@ -0,0 +1,57 @@
+package keepertest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tooky0630/keeper"
+)
+
+type pool struct{}
+
+func (*pool) Destroy() error { return nil }
+
+type subscriber struct {
+	Pool *pool `name:"pool"`
+}
+
+func (*subscriber) Destroy() error { return nil }
+
+func TestTeardown(t *testing.T) {
+	td := NewTeardown()
+	c := keeper.New(td.Option())
+	c.Register(new(pool), keeper.Name("pool"))
+	c.Register(new(subscriber), keeper.Name("subscriber"))
+	td.Check(t, c)
+	if got := td.Order(); !reflect.DeepEqual(got, []string{"subscriber", "pool"}) {
+		t.Fatalf("got %v", got)
+	}
+}
+
+func TestTeardownViolation(t *testing.T) {
+	td := NewTeardown()
+	c := keeper.New(td.Option(), keeper.OnMissing(keeper.MissingDefer))
+	c.Register(new(subscriber), keeper.Name("subscriber")) // pool is injected by Build
+	c.Register(new(pool), keeper.Name("pool"))
+	if err := c.Build(); err != nil {
+		t.Fatal(err)
+	}
+	c.Close()
+	want := []string{"pool was destroyed before its dependent subscriber"}
+	if got := td.violations(c); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestTeardownDelay(t *testing.T) {
+	td := NewTeardown().Delay("pool", 50*time.Millisecond)
+	c := keeper.New(td.Option())
+	c.Register(new(pool), keeper.Name("pool"))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if err := c.V2().Close(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package keepertest
+
+import (
+	"testing"
+
+	"github.com/tooky0630/keeper"
+)
+
+// Wire builds a throwaway container holding the overrides, registered in
+// order, and the beans of the keeper.Default container they don't override,
+// then injects the fields of fixture from it. The beans of the Default
+// container are adopted as they are, they are neither reinjected nor
+// destroyed; the container is closed when the test ends.
+//
+//	type fixture struct {
+//		svc  *Service `name:"service"`
+//		repo *Repo    `name:"repo"`
+//	}
+//
+//	f := new(fixture)
+//	keepertest.Wire(t, f, keeper.Bean(fakeRepo, keeper.Name("repo")))
+func Wire(t testing.TB, fixture interface{}, overrides ...keeper.Registration) keeper.Keeper {
+	t.Helper()
+	c := keeper.New()
+	t.Cleanup(func() {
+		if err := c.Close(); err != nil {
+			t.Errorf("keepertest: failed to close the container: %v", err)
+		}
+	})
+	if err := c.RegisterBatch(overrides...); err != nil {
+		t.Fatalf("keepertest: failed to register overrides: %v", err)
+	}
+	overridden := make(map[string]bool)
+	for _, info := range c.Beans() {
+		overridden[info.Name] = true
+	}
+	defaults := keeper.Default()
+	for _, info := range defaults.Beans() {
+		if overridden[info.Name] {
+			continue
+		}
+		if err := c.Adopt(info.Name, defaults.Find(info.Name), keeper.ReadOnly()); err != nil {
+			t.Fatalf("keepertest: failed to adopt %s: %v", info.Name, err)
+		}
+	}
+	if err := c.Provider(fixture); err != nil {
+		t.Fatalf("keepertest: failed to wire %T: %v", fixture, err)
+	}
+	return c
+}
@@ -0,0 +1,40 @@
+package keepertest
+
+import (
+	"testing"
+
+	"github.com/tooky0630/keeper"
+)
+
+type repo struct {
+	name string
+}
+
+// the fields are exported for the keeper_safe build
+type service struct {
+	Repo *repo `name:"repo"`
+}
+
+type fixture struct {
+	Svc   *service `name:"service"`
+	Repo  *repo    `name:"repo"`
+	Clock *repo    `name:"clock"`
+}
+
+func TestWire(t *testing.T) {
+	keeper.ResetDefault()
+	defer keeper.ResetDefault()
+	keeper.RegisterDefault(&repo{name: "clock"}, keeper.Name("clock"))
+	keeper.RegisterDefault(&repo{name: "mysql"}, keeper.Name("repo"))
+
+	f := new(fixture)
+	Wire(t, f,
+		keeper.Bean(&repo{name: "fake"}, keeper.Name("repo")),
+		keeper.Bean(new(service), keeper.Name("service")))
+	if f.Repo.name != "fake" || f.Svc.Repo != f.Repo {
+		t.Fatalf("overrides should replace defaults, got %+v", f)
+	}
+	if f.Clock == nil || f.Clock != keeper.FindDefault("clock") {
+		t.Fatal("defaults should be adopted")
+	}
+}
@@ -0,0 +1,137 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrStarted is returned by Start when the container has started already.
+var ErrStarted = errors.New("container already started")
+
+// Disposer is implemented by beans which release resources, like connections
+// and files, when the container is closed.
+type Disposer interface {
+	Destroy() error
+}
+
+// Starter is implemented by beans which have work to do once the whole
+// container has been assembled, such as mounting routes or opening listeners.
+type Starter interface {
+	Start(ctx context.Context) error
+}
+
+// InitOnStart is a RegisterOption which leaves AfterPropertySet of the bean
+// to Start, for initializers using beans registered later. Once started,
+// the bean is initialized by Register as usual.
+func InitOnStart() RegisterOption {
+	return registerOptionFunc(func(options *registerOptions) {
+		options.InitOnStart = true
+	})
+}
+
+// NoInit is a RegisterOption which keeps keeper from invoking AfterPropertySet
+// of the bean, for types which implement Initializer for another lifecycle
+// manager initializing them. It takes precedence over InitOnStart.
+func NoInit() RegisterOption {
+	return registerOptionFunc(func(options *registerOptions) {
+		options.NoInit = true
+	})
+}
+
+// Start invokes Start of every Starter bean in registration order, and stops
+// at the first failure, then runs the StartTask funcs concurrently and
+// PhaseStart. Worker and Scheduled beans are run once all beans have started.
+// Dependencies deferred by MissingDefer are resolved by Build first, then
+// the beans registered with InitOnStart are initialized in registration order,
+// and the first to fail, as an *InitError, fails Start. Start fails with
+// ErrStarted once called, until Reset.
+func (c *Container) Start(ctx context.Context) error {
+	if err := c.Build(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	if c.started {
+		c.mu.Unlock()
+		return ErrStarted
+	}
+	c.started = true
+	pending := c.initPending
+	c.initPending = nil
+	c.mu.Unlock()
+	for _, name := range pending {
+		c.mu.RLock()
+		options := c.opts[name]
+		c.mu.RUnlock()
+		if err := c.initializeOnStart(ctx, name, options); err != nil {
+			c.recordError(name, err)
+			return err
+		}
+	}
+	for _, nb := range c.ordered() {
+		starter, ok := nb.bean.(Starter)
+		if !ok {
+			continue
+		}
+		if err := c.start(ctx, nb.name, starter); err != nil {
+			c.recordError(nb.name, err)
+			return fmt.Errorf("failed to start %s: %v", nb.name, err)
+		}
+	}
+	if err := c.runStartTasks(ctx); err != nil {
+		return err
+	}
+	if err := c.RunPhase(ctx, PhaseStart); err != nil {
+		return err
+	}
+	return c.startWorkers()
+}
+
+func (c *Container) start(ctx context.Context, name string, starter Starter) error {
+	defer c.dumpOnPanic(name)
+	return starter.Start(ctx)
+}
+
+func (c *Container) initializeOnStart(ctx context.Context, name string, options registerOptions) error {
+	defer c.dumpOnPanic(name)
+	_, err := c.initialize(ctx, c.published()[name], options, nil)
+	return err
+}
+
+// Close stops the workers and runs PhaseStop, then destroys every Disposer
+// bean in reverse registration order, so beans are destroyed after their
+// dependents. ReadOnly beans are never destroyed. Close is a no-op once closed.
+// A container created by NewNamed can't be found by Lookup anymore.
+func (c *Container) Close() error {
+	c.mu.Lock()
+	closed := c.closed
+	c.closed = true
+	c.mu.Unlock()
+	if closed {
+		return nil
+	}
+	c.forget()
+	return c.dispose()
+}
+
+// dispose stops the workers, runs PhaseStop and destroys the beans.
+func (c *Container) dispose() error {
+	c.stopAllWorkers()
+	var errs multiError
+	if err := c.RunPhase(context.Background(), PhaseStop); err != nil {
+		errs = append(errs, err)
+	}
+	beans := c.ordered()
+	for i := len(beans) - 1; i >= 0; i-- {
+		d, ok := beans[i].bean.(Disposer)
+		if !ok || c.isReadOnly(beans[i].name) {
+			continue
+		}
+		c.notifyDestroy(beans[i].name)
+		if err := d.Destroy(); err != nil {
+			c.recordError(beans[i].name, err)
+			errs = append(errs, fmt.Errorf("failed to destroy %s: %v", beans[i].name, err))
+		}
+	}
+	return errs.errOrNil()
+}
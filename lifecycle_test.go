@@ -0,0 +1,57 @@
+package keeper
+
+import (
+	"context"
+	"testing"
+)
+
+type lateInitializer struct {
+	c         Keeper
+	found     interface{}
+	initCount int
+}
+
+func (l *lateInitializer) AfterPropertySet() {
+	l.found = l.c.Find("registeredLater")
+	l.initCount++
+}
+
+func TestContainer_InitOnStart(t *testing.T) {
+	c := New()
+	bean := &lateInitializer{c: c}
+	if err := c.Register(bean, Name("late"), InitOnStart()); err != nil {
+		t.Fatal(err)
+	}
+	if bean.initCount != 0 {
+		t.Fatal("AfterPropertySet should wait for Start")
+	}
+	c.Register(&HelloSrv{}, Name("registeredLater"))
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if bean.initCount != 1 || bean.found == nil {
+		t.Fatalf("got %+v after Start", bean)
+	}
+
+	afterStart := &lateInitializer{c: c}
+	c.Register(afterStart, Name("afterStart"), InitOnStart())
+	if afterStart.initCount != 1 {
+		t.Fatal("beans registered after Start should be initialized by Register")
+	}
+}
+
+func TestContainer_NoInit(t *testing.T) {
+	c := New()
+	bean := &lateInitializer{c: c}
+	if err := c.Register(bean, Name("shared"), NoInit()); err != nil {
+		t.Fatal(err)
+	}
+	late := &lateInitializer{c: c}
+	c.Register(late, Name("late"), NoInit(), InitOnStart())
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if bean.initCount != 0 || late.initCount != 0 {
+		t.Fatal("NoInit beans should never be initialized by keeper")
+	}
+}
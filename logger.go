@@ -0,0 +1,53 @@
+package keeper
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+)
+
+// Logger receives the progress messages of the container, *log.Logger satisfies it.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Printf(string, ...interface{}) {}
+
+// WithLogger is an Option that reports the progress of the container to l.
+func WithLogger(l Logger) Option {
+	return optionFunc(func(c *Container) {
+		c.logger = l
+	})
+}
+
+var _slogType = reflect.TypeOf((*slog.Logger)(nil))
+
+// WithSlog is an Option that injects the untagged *slog.Logger fields of the
+// beans with root annotated with the name of the bean, component=<name>.
+// Fields tagged with a bean name get that bean as usual.
+func WithSlog(root *slog.Logger) Option {
+	return optionFunc(func(c *Container) {
+		c.slog = root
+	})
+}
+
+// setSlog sets the i-th field of the struct ptr points to, a *slog.Logger,
+// to the logger of the bean, if it isn't set already.
+func (c *Container) setSlog(ptr interface{}, i int, bean string) error {
+	fv := reflect.ValueOf(ptr).Elem().Field(i)
+	if !fv.IsNil() {
+		return nil
+	}
+	logger := c.slog
+	if bean != "" {
+		logger = logger.With("component", bean)
+	}
+	fv, err := settable(fv)
+	if err != nil {
+		return fmt.Errorf("failed to set logger %s: %v", fieldPath(ptr, i), err)
+	}
+	fv.Set(reflect.ValueOf(logger))
+	return nil
+}
@@ -0,0 +1,29 @@
+package keeper
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type auditedSrv struct {
+	log *slog.Logger
+}
+
+func TestContainer_WithSlog(t *testing.T) {
+	unsafeOnly(t)
+	var buf bytes.Buffer
+	c := New(WithSlog(slog.New(slog.NewTextHandler(&buf, nil))))
+	srv := new(auditedSrv)
+	if err := c.Register(srv, Name("auditedSrv")); err != nil {
+		t.Fatal(err)
+	}
+	srv.log.Info("started")
+	if !strings.Contains(buf.String(), "msg=started component=auditedSrv") {
+		t.Fatalf("got %q", buf.String())
+	}
+	if err := New().Register(new(auditedSrv), Name("auditedSrv")); err != nil {
+		t.Fatal(err)
+	}
+}
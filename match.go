@@ -0,0 +1,109 @@
+package keeper
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// FindMatching returns the beans whose names match the glob pattern, with
+// the syntax of path.Match: "consumer.*" matches "consumer.orders". Like
+// All, it builds the factory beans which aren't built yet.
+func (c *Container) FindMatching(pattern string) (map[string]interface{}, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	return c.findNames(func(name string) bool {
+		ok, _ := path.Match(pattern, name)
+		return ok
+	}), nil
+}
+
+// FindRegexp returns the beans whose names match re.
+func (c *Container) FindRegexp(re *regexp.Regexp) map[string]interface{} {
+	return c.findNames(re.MatchString)
+}
+
+// AllWithPrefix returns the beans whose names start with prefix. Like All,
+// it builds the factory beans which aren't built yet.
+func (c *Container) AllWithPrefix(prefix string) map[string]interface{} {
+	return c.findNames(func(name string) bool {
+		return strings.HasPrefix(name, prefix)
+	})
+}
+
+// AllLabeled returns the beans registered with the Label key=value. Like
+// All, it builds the factory beans which aren't built yet.
+func (c *Container) AllLabeled(key, value string) map[string]interface{} {
+	return c.findNames(func(name string) bool {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+		v, ok := c.opts[name].Labels[key]
+		return ok && v == value
+	})
+}
+
+// AllOf returns the beans of k which are a T, like the implementations of an
+// interface, by name. Like All, it builds the factory beans which aren't
+// built yet, whatever their type:
+//
+//	handlers := keeper.AllOf[http.Handler](c)
+func AllOf[T any](k Keeper) map[string]T {
+	beans := make(map[string]T)
+	k.Range(func(name string, bean interface{}) bool {
+		if t, ok := bean.(T); ok {
+			beans[name] = t
+		}
+		return true
+	})
+	return beans
+}
+
+// ListOf returns the beans of k which are a T in the Ordering of k.
+func ListOf[T any](k Keeper) []T {
+	var beans []T
+	k.Range(func(_ string, bean interface{}) bool {
+		if t, ok := bean.(T); ok {
+			beans = append(beans, t)
+		}
+		return true
+	})
+	return beans
+}
+
+// Range calls fn for each bean in the Ordering of the container, until fn
+// returns false. Like All, it builds the factory beans which aren't built
+// yet, but it doesn't copy the beans: the beans registered meanwhile may be missed.
+func (c *Container) Range(fn func(name string, bean interface{}) bool) {
+	c.mu.RLock()
+	names := c.enumeration()
+	c.mu.RUnlock()
+	nodes := c.published()
+	for _, name := range names {
+		bean := nodes[name]
+		switch b := bean.(type) {
+		case *lazyBean:
+			if bean = c.build(name, b); bean == nil {
+				continue
+			}
+		case *beanGroup: // the members are listed
+			continue
+		case nil: // swapped out
+			continue
+		}
+		if !fn(name, bean) {
+			return
+		}
+	}
+}
+
+func (c *Container) findNames(match func(name string) bool) map[string]interface{} {
+	cm := make(map[string]interface{})
+	c.Range(func(name string, bean interface{}) bool {
+		if match(name) {
+			cm[name] = bean
+		}
+		return true
+	})
+	return cm
+}
@@ -0,0 +1,94 @@
+package keeper
+
+import (
+	"regexp"
+	"testing"
+)
+
+func newConsumers() Keeper {
+	c := New()
+	c.Register(&HelloSrv{}, Name("consumer.orders"))
+	c.Register(&HelloSrv{}, Name("consumer.payments"))
+	c.Register(&HelloSrv{}, Name("producer.orders"))
+	c.RegisterFactory(func(Keeper) (interface{}, error) { return &HelloSrv{}, nil }, Name("consumer.lazy"))
+	return c
+}
+
+func TestContainer_FindMatching(t *testing.T) {
+	beans, err := newConsumers().FindMatching("consumer.*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(beans) != 3 || beans["consumer.lazy"] == nil {
+		t.Fatalf("got %v", beans)
+	}
+	if _, err := newConsumers().FindMatching("consumer.["); err == nil {
+		t.Fatal("expected error for malformed pattern")
+	}
+}
+
+func TestContainer_FindRegexp(t *testing.T) {
+	beans := newConsumers().FindRegexp(regexp.MustCompile(`\.orders$`))
+	if len(beans) != 2 || beans["producer.orders"] == nil {
+		t.Fatalf("got %v", beans)
+	}
+}
+
+func TestContainer_Range(t *testing.T) {
+	c := New()
+	c.Register(&HelloSrv{}, Name("consumer.orders"))
+	c.Register(&HelloSrv{}, Name("consumer.payments"))
+	c.RegisterFactory(func(Keeper) (interface{}, error) {
+		return &HelloSrv{}, nil
+	}, Name("consumer.refunds"))
+	seen := make(map[string]bool)
+	c.Range(func(name string, bean interface{}) bool {
+		if _, ok := bean.(*HelloSrv); !ok {
+			t.Fatalf("%s: got %T", name, bean)
+		}
+		seen[name] = true
+		return true
+	})
+	if len(seen) != 3 {
+		t.Fatalf("got %v", seen)
+	}
+	calls := 0
+	c.Range(func(string, interface{}) bool {
+		calls++
+		return false
+	})
+	if calls != 1 {
+		t.Fatalf("Range went on after false, %d calls", calls)
+	}
+}
+
+func TestContainer_AllWithPrefix(t *testing.T) {
+	beans := newConsumers().AllWithPrefix("consumer.")
+	if len(beans) != 3 || beans["producer.orders"] != nil {
+		t.Fatalf("got %v", beans)
+	}
+}
+
+func TestContainer_AllLabeled(t *testing.T) {
+	c := New()
+	c.Register(&HelloSrv{}, Name("a"), Label("tier", "web"))
+	c.Register(&HelloSrv{}, Name("b"), Label("tier", "batch"))
+	c.Register(&HelloSrv{}, Name("c"))
+	beans := c.AllLabeled("tier", "web")
+	if len(beans) != 1 || beans["a"] == nil {
+		t.Fatalf("got %v", beans)
+	}
+}
+
+func TestAllOf(t *testing.T) {
+	c := newConsumers()
+	c.Register(new(politeGreeter), Name("greeter"))
+	srvs := AllOf[*HelloSrv](c)
+	if len(srvs) != 4 || srvs["consumer.lazy"] == nil {
+		t.Fatalf("got %v", srvs)
+	}
+	greeters := ListOf[greeterService](c)
+	if len(greeters) != 1 || greeters[0].Greet() != "good morning" {
+		t.Fatalf("got %v", greeters)
+	}
+}
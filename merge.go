@@ -0,0 +1,198 @@
+package keeper
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ConflictPolicy decides what Merge does with the names both containers have.
+type ConflictPolicy int
+
+const (
+	// ConflictError fails the merge without importing anything, it's the default.
+	ConflictError ConflictPolicy = iota
+	// ConflictKeepOurs keeps the bean of the container merged into.
+	ConflictKeepOurs
+	// ConflictTakeTheirs replaces the bean with the one of the merged container.
+	ConflictTakeTheirs
+)
+
+// Merge imports the beans of other, which should not be used anymore, after
+// the beans of c and in the registration order of other. The dependents of
+// a conflicting name, in both containers, are rewired to the bean kept by
+// policy, and the dependencies other deferred are resolved by the next Build.
+// Beans aren't loaded again, so their initializers don't run twice; the ones
+// registered with InitOnStart are initialized by Start of c, and the typed
+// factories and the flags gating beans are imported along.
+func (c *Container) Merge(other Keeper, policy ConflictPolicy) error {
+	o, ok := other.(*Container)
+	if !ok {
+		return fmt.Errorf("cannot merge %T, only containers created by New", other)
+	}
+	if o == c {
+		return fmt.Errorf("cannot merge a container into itself")
+	}
+	o.mu.RLock()
+	theirs := o.published()
+	order := append([]string(nil), o.order...)
+	opts := make(map[string]registerOptions, len(o.opts))
+	deps := make(map[string][]string, len(o.deps))
+	for _, name := range order {
+		opts[name] = o.opts[name]
+		deps[name] = o.deps[name]
+	}
+	injected := append([]injectedField(nil), o.injected...)
+	deferred := append([]deferredField(nil), o.deferred...)
+	values := append([]valueBinding(nil), o.values...)
+	optional := append([]optionalField(nil), o.optional...)
+	initPending := append([]string(nil), o.initPending...)
+	typed := make(map[reflect.Type]*lazyBean, len(o.typed))
+	for typ, lazy := range o.typed {
+		typed[typ] = lazy
+	}
+	gates := make(map[string]*flagGate, len(o.gates))
+	for name, g := range o.gates {
+		gates[name] = g
+	}
+	o.mu.RUnlock()
+
+	c.mu.Lock()
+	ours := c.published()
+	conflicts := make(map[string]bool)
+	for _, name := range order {
+		if _, ok := ours[name]; ok {
+			conflicts[name] = true
+		}
+	}
+	var typeConflicts []string
+	for typ := range typed {
+		if _, ok := c.typed[typ]; ok {
+			typeConflicts = append(typeConflicts, "the factory of "+typ.String())
+		}
+	}
+	if (len(conflicts) > 0 || len(typeConflicts) > 0) && policy == ConflictError {
+		c.mu.Unlock()
+		names := make([]string, 0, len(conflicts))
+		for name := range conflicts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		sort.Strings(typeConflicts)
+		return fmt.Errorf("cannot merge, both containers have %s", strings.Join(append(names, typeConflicts...), ", "))
+	}
+	if c.started && len(initPending) > 0 {
+		c.mu.Unlock()
+		return fmt.Errorf("cannot merge, %s is registered with InitOnStart and the container has started", initPending[0])
+	}
+	dropped := make(map[string]bool) // the beans which lost a conflict
+	next := make(map[string]interface{}, len(ours)+len(theirs))
+	for name, bean := range ours {
+		next[name] = bean
+	}
+	var merged []string // nothing is changed until every group is joined
+	for _, name := range order {
+		if conflicts[name] && policy == ConflictKeepOurs {
+			dropped[name] = true
+			continue
+		}
+		options := opts[name]
+		next[name] = theirs[name]
+		merged = append(merged, name)
+		if options.Group != "" && !conflicts[name] {
+			g, err := c.join(next, options)
+			if err != nil {
+				c.mu.Unlock()
+				return err
+			}
+			next[options.Group] = g
+		}
+	}
+	for _, name := range merged {
+		if !conflicts[name] {
+			c.order = append(c.order, name)
+		}
+		c.opts[name] = opts[name]
+		c.deps[name] = deps[name]
+	}
+	if policy == ConflictTakeTheirs {
+		kept := c.injected[:0:0]
+		for _, f := range c.injected {
+			if !conflicts[f.bean] {
+				kept = append(kept, f)
+			}
+		}
+		c.injected = kept
+		keptOptional := c.optional[:0:0]
+		for _, f := range c.optional {
+			if !conflicts[f.bean] {
+				keptOptional = append(keptOptional, f)
+			}
+		}
+		c.optional = keptOptional
+		for name := range conflicts {
+			delete(c.gates, name)
+		}
+	}
+	for typ, lazy := range typed {
+		if _, ok := c.typed[typ]; !ok || policy == ConflictTakeTheirs {
+			c.typed[typ] = lazy
+		}
+	}
+	var flags []string // the gates follow the flags of c from now on
+	for name, g := range gates {
+		if !dropped[name] {
+			if c.gates == nil {
+				c.gates = make(map[string]*flagGate)
+			}
+			c.gates[name] = g
+			flags = append(flags, g.flag)
+		}
+	}
+	for _, name := range initPending {
+		if !dropped[name] {
+			c.initPending = append(c.initPending, name)
+		}
+	}
+	for _, f := range optional {
+		if !dropped[f.bean] {
+			c.optional = append(c.optional, f)
+		}
+	}
+	for _, f := range injected {
+		if !dropped[f.bean] {
+			c.injected = append(c.injected, f)
+		}
+	}
+	for _, d := range deferred {
+		if !dropped[d.bean] {
+			c.deferred = append(c.deferred, d)
+		}
+	}
+	for _, v := range values {
+		if !dropped[v.bean] {
+			c.values = append(c.values, v)
+		}
+	}
+	c.nodes.Store(next)
+	var rewire []injectedField
+	for _, f := range c.injected {
+		if conflicts[f.source] {
+			rewire = append(rewire, f)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, f := range rewire {
+		if err := c.reinject(f, c.Find(f.source)); err != nil {
+			return fmt.Errorf("merged, but %v", err)
+		}
+	}
+	if len(flags) > 0 {
+		if err := c.evaluateFlags(flags); err != nil {
+			return fmt.Errorf("merged, but %v", err)
+		}
+	}
+	return nil
+}
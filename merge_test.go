@@ -0,0 +1,112 @@
+package keeper
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func newMergeContainer(word string) (Keeper, *HelloCtl) {
+	c := New()
+	c.Register(&HelloSrv{word: word}, Name("helloService"))
+	ctl := new(HelloCtl)
+	c.Register(ctl, Name(word+"Ctl"))
+	return c, ctl
+}
+
+func TestContainer_Merge(t *testing.T) {
+	unsafeOnly(t)
+	tests := []struct {
+		policy             ConflictPolicy
+		oursCtl, theirsCtl string
+	}{
+		{ConflictKeepOurs, "ours", "ours"},
+		{ConflictTakeTheirs, "theirs", "theirs"},
+	}
+	for _, tt := range tests {
+		c, oursCtl := newMergeContainer("ours")
+		other, theirsCtl := newMergeContainer("theirs")
+		if err := c.Merge(other, tt.policy); err != nil {
+			t.Fatal(err)
+		}
+		if oursCtl.helloSrv.word != tt.oursCtl || theirsCtl.helloSrv.word != tt.theirsCtl {
+			t.Errorf("policy %d: got %s and %s", tt.policy, oursCtl.helloSrv.word, theirsCtl.helloSrv.word)
+		}
+		if c.Find("theirsCtl") == nil || len(c.Beans()) != 3 {
+			t.Errorf("policy %d: got beans %v", tt.policy, c.Beans())
+		}
+	}
+}
+
+func TestContainer_MergeConflict(t *testing.T) {
+	c, _ := newMergeContainer("ours")
+	other, _ := newMergeContainer("theirs")
+	if err := c.Merge(other, ConflictError); err == nil {
+		t.Fatal("expected conflict error")
+	}
+	if c.Find("theirsCtl") != nil {
+		t.Fatal("nothing should be imported on conflict")
+	}
+}
+
+func TestContainer_MergeDeferred(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	c.Register(&HelloSrv{word: "ours"}, Name("helloService"))
+	other := New(OnMissing(MissingDefer))
+	ctl := new(HelloCtl)
+	other.Register(ctl, Name("helloCtl"))
+	if err := c.Merge(other, ConflictError); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if ctl.helloSrv.word != "ours" {
+		t.Fatal("deferred dependency should be resolved across containers")
+	}
+}
+
+func TestContainer_MergeGroupCollision(t *testing.T) {
+	c := New()
+	c.Register(&HelloSrv{}, Name("grp"))
+	other := New()
+	other.Register(&HelloSrv{}, Name("x"))
+	other.Register(&HelloSrv{}, Name("m"), Group("grp"))
+	if err := c.Merge(other, ConflictError); err == nil {
+		t.Fatal("expected error for a member of a group named like a bean")
+	}
+	if beans := c.Beans(); len(beans) != 1 || beans[0].Name != "grp" {
+		t.Fatalf("a failed Merge left %v", beans)
+	}
+}
+
+func TestContainer_MergeState(t *testing.T) {
+	flags := &fakeFlags{enabled: map[string]bool{"new-hello": true}}
+	c := New(WithFlags(flags))
+	other := New()
+	late := &lateInitializer{c: c}
+	other.Register(late, Name("late"), InitOnStart())
+	other.Register(&HelloSrv{word: "new"}, Name("helloService"), IfFlag("new-hello", &HelloSrv{word: "legacy"}))
+	typ := reflect.TypeOf((*schemaRepo)(nil))
+	other.RegisterTyped(typ, func(Keeper) (interface{}, error) { return &schemaRepo{table: "users"}, nil })
+	if err := c.Merge(other, ConflictError); err != nil {
+		t.Fatal(err)
+	}
+	if srv := c.Find("helloService").(*HelloSrv); srv.word != "new" {
+		t.Fatalf("got %s, the gate should follow the flags of the container merged into", srv.word)
+	}
+	if _, err := c.ResolveType(typ); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if late.initCount != 1 {
+		t.Fatal("InitOnStart beans should be initialized by Start of the container merged into")
+	}
+	flags.set("new-hello", false)
+	if srv := c.Find("helloService").(*HelloSrv); srv.word != "legacy" {
+		t.Fatalf("got %s after disabling the flag", srv.word)
+	}
+}
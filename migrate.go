@@ -0,0 +1,69 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Migrator is implemented by beans which prepare external state, like database
+// schemas, before the application serves.
+type Migrator interface {
+	Migrate(ctx context.Context) error
+}
+
+// Migrate runs every Migrator bean after the beans it depends on. A failed
+// migration doesn't stop the others, only the beans depending on it are
+// skipped, and all failures are returned together.
+func (c *Container) Migrate(ctx context.Context) error {
+	var errs multiError
+	failed := make(map[string]bool)
+	for _, nb := range c.ordered() { // dependencies are always registered first
+		name := nb.name
+		if dep := c.failedDep(name, failed); dep != "" {
+			failed[name] = true
+			if _, ok := nb.bean.(Migrator); ok {
+				c.logger.Printf("keeper: migration %s skipped, %s failed", name, dep)
+				errs = append(errs, fmt.Errorf("migration %s skipped: dependency %s failed", name, dep))
+			}
+			continue
+		}
+		m, ok := nb.bean.(Migrator)
+		if !ok {
+			continue
+		}
+		c.logger.Printf("keeper: migration %s started", name)
+		begin := time.Now()
+		if err := runMigration(ctx, m); err != nil {
+			failed[name] = true
+			c.logger.Printf("keeper: migration %s failed: %v", name, err)
+			c.recordError(name, err)
+			errs = append(errs, fmt.Errorf("migration %s failed: %v", name, err))
+			continue
+		}
+		c.logger.Printf("keeper: migration %s finished in %v", name, time.Since(begin))
+	}
+	return errs.errOrNil()
+}
+
+// failedDep returns the first dependency of name which failed.
+func (c *Container) failedDep(name string, failed map[string]bool) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, dep := range c.deps[name] {
+		if failed[dep] {
+			return dep
+		}
+	}
+	return ""
+}
+
+// runMigration runs m, turning a panic into an error.
+func runMigration(ctx context.Context, m Migrator) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return m.Migrate(ctx)
+}
@@ -0,0 +1,74 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type schema struct {
+	ran *[]string
+	err error
+}
+
+func (s *schema) Migrate(context.Context) error {
+	*s.ran = append(*s.ran, "schema")
+	return s.err
+}
+
+type seed struct {
+	schema *schema `name:"schema"`
+	ran    *[]string
+}
+
+func (s *seed) Migrate(context.Context) error {
+	*s.ran = append(*s.ran, "seed")
+	return nil
+}
+
+type index struct {
+	ran *[]string
+}
+
+func (i *index) Migrate(context.Context) error {
+	*i.ran = append(*i.ran, "index")
+	return nil
+}
+
+func TestContainer_Migrate(t *testing.T) {
+	unsafeOnly(t)
+	var ran []string
+	c := New()
+	if err := c.Register(&schema{ran: &ran}, Name("schema")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Register(&seed{ran: &ran}, Name("seed")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Migrate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(ran, ",") != "schema,seed" {
+		t.Fatalf("got migrations %v", ran)
+	}
+}
+
+func TestContainer_MigrateIsolatesFailure(t *testing.T) {
+	unsafeOnly(t)
+	var ran []string
+	c := New()
+	c.Register(&schema{ran: &ran, err: errors.New("table exists")}, Name("schema"))
+	c.Register(&seed{ran: &ran}, Name("seed"))
+	c.Register(&index{ran: &ran}, Name("index"))
+	err := c.Migrate(context.Background())
+	if err == nil {
+		t.Fatal("expected migration error")
+	}
+	if !strings.Contains(err.Error(), "seed skipped") {
+		t.Fatalf("seed should be skipped: %v", err)
+	}
+	if strings.Join(ran, ",") != "schema,index" {
+		t.Fatalf("got migrations %v", ran)
+	}
+}
@@ -0,0 +1,146 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// MissingPolicy decides what happens when a required dependency isn't registered.
+type MissingPolicy int
+
+const (
+	// MissingError fails the registration, it's the default.
+	MissingError MissingPolicy = iota
+	// MissingWarn logs the missing dependency and leaves the field zero.
+	MissingWarn
+	// MissingDefer leaves the field zero and retries it on Build or Start,
+	// which fail if the dependency is still missing. It suits beans
+	// registered before the plugins they depend on.
+	MissingDefer
+)
+
+// OnMissing is an Option that sets the MissingPolicy of the container.
+// Optional dependencies are never affected.
+func OnMissing(policy MissingPolicy) Option {
+	return optionFunc(func(c *Container) {
+		c.missingPolicy = policy
+	})
+}
+
+// deferredField is a field whose dependency was missing under MissingDefer.
+type deferredField struct {
+	bean    string
+	ptr     interface{}
+	field   int
+	options registerOptions
+}
+
+// Build injects the dependencies deferred by MissingDefer. The ones still
+// missing are returned as WiringErrors and stay deferred for the next Build.
+// Note that the initializers of these beans have already run without them.
+// Once every dependency is injected, Build checks the schedules of the
+// Scheduled beans, so that Start doesn't fail once the beans have started,
+// evaluates the flags of the beans registered with IfFlag, then checks the
+// Invariant options.
+func (c *Container) Build() error {
+	if errs := c.injectDeferred(func(string) bool { return true }); len(errs) > 0 {
+		return errs
+	}
+	if _, err := c.scheduledJobs(); err != nil {
+		return err
+	}
+	if err := c.evaluateFlags(nil); err != nil {
+		return err
+	}
+	return c.checkInvariants()
+}
+
+// BuildFor builds only what the roots need, so that a CLI running one of its
+// subcommands doesn't build the whole graph: the factory beans among roots,
+// with whatever their factories resolve, then the dependencies deferred by
+// MissingDefer of the roots and of the beans they depend on. The other
+// factory beans are left unbuilt and the Invariant options unchecked, as
+// they apply to the whole graph.
+func (c *Container) BuildFor(roots ...string) error {
+	nodes := c.published()
+	for _, name := range roots {
+		bean, ok := nodes[name]
+		if !ok {
+			return fmt.Errorf("cannot build for %s, it isn't registered", name)
+		}
+		if lazy, ok := bean.(*lazyBean); ok {
+			if _, err := c.buildErr(nil, name, lazy); err != nil {
+				return fmt.Errorf("failed to build %s: %v", name, err)
+			}
+		}
+	}
+	closure := c.closure(roots)
+	if errs := c.injectDeferred(func(bean string) bool { return closure[bean] }); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// closure returns the roots and the beans they depend on, transitively.
+func (c *Container) closure(roots []string) map[string]bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	closure := make(map[string]bool)
+	var visit func(name string)
+	visit = func(name string) {
+		if closure[name] {
+			return
+		}
+		closure[name] = true
+		for _, dep := range c.deps[name] {
+			visit(dep)
+		}
+	}
+	for _, name := range roots {
+		visit(name)
+	}
+	return closure
+}
+
+// injectDeferred injects the deferred dependencies of the beans selected by
+// want, and returns the ones still missing, which stay deferred.
+func (c *Container) injectDeferred(want func(bean string) bool) WiringErrors {
+	c.mu.Lock()
+	var deferred, pending []deferredField
+	for _, d := range c.deferred {
+		if want(d.bean) {
+			deferred = append(deferred, d)
+		} else {
+			pending = append(pending, d)
+		}
+	}
+	c.deferred = nil
+	c.mu.Unlock()
+
+	var errs WiringErrors
+	for _, d := range deferred {
+		if werr := c.checkField(d.bean, reflect.TypeOf(d.ptr).Elem(), d.field, d.options); werr != nil {
+			errs = append(errs, werr)
+			pending = append(pending, d)
+			continue
+		}
+		var w wiring
+		if _, err := c.loadField(context.Background(), d.ptr, d.field, d.options, MissingError, &w); err != nil {
+			errs = append(errs, &WiringError{Bean: d.bean, Field: reflect.TypeOf(d.ptr).Elem().Field(d.field).Name, Got: err.Error(), Site: d.options.site})
+			pending = append(pending, d)
+			continue
+		}
+		if d.bean == "" { // loaded by Provider
+			continue
+		}
+		c.mu.Lock()
+		c.deps[d.bean] = append(c.deps[d.bean], w.deps...)
+		c.injected = append(c.injected, w.fields...)
+		c.mu.Unlock()
+	}
+	c.mu.Lock()
+	c.deferred = append(pending, c.deferred...)
+	c.mu.Unlock()
+	return errs
+}
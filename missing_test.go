@@ -0,0 +1,95 @@
+package keeper
+
+import (
+	"context"
+	"testing"
+)
+
+func TestContainer_OnMissingWarn(t *testing.T) {
+	c := New(OnMissing(MissingWarn))
+	ctl := new(HelloCtl)
+	if err := c.Register(ctl, Name("helloCtl")); err != nil {
+		t.Fatal(err)
+	}
+	if ctl.helloSrv.word != "" {
+		t.Fatal("missing dependency should be left zero")
+	}
+}
+
+func TestContainer_OnMissingDefer(t *testing.T) {
+	unsafeOnly(t)
+	c := New(OnMissing(MissingDefer))
+	ctl := new(HelloCtl)
+	if err := c.Register(ctl, Name("helloCtl")); err != nil {
+		t.Fatal(err)
+	}
+	c.Register(&HelloSrv{word: "late"}, Name("helloService"))
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if ctl.helloSrv.word != "late" {
+		t.Fatal("deferred dependency should be injected on Start")
+	}
+	if deps := c.Beans()[0].Dependencies; len(deps) != 1 || deps[0] != "helloService" {
+		t.Fatalf("got dependencies %v", deps)
+	}
+}
+
+func TestContainer_OnMissingDeferExpand(t *testing.T) {
+	unsafeOnly(t)
+	c := New(OnMissing(MissingDefer), WithConfig(&mapSource{values: map[string]string{"REGION": "eu", "SHARD": "7"}}))
+	ctl := new(regionalCtl)
+	if err := c.Register(ctl, Name("regionalCtl")); err != nil {
+		t.Fatal(err)
+	}
+	eu := &HelloSrv{word: "hallo"}
+	c.Register(eu, Name("hello-eu"))
+	if err := c.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if ctl.helloSrv != eu || ctl.fallback != eu {
+		t.Fatalf("got %+v", ctl)
+	}
+}
+
+func TestContainer_BuildMissing(t *testing.T) {
+	c := New(OnMissing(MissingDefer))
+	c.Register(new(HelloCtl), Name("helloCtl"))
+	if err := c.Build(); err == nil {
+		t.Fatal("expected error for a dependency still missing")
+	}
+}
+
+func TestContainer_BuildFor(t *testing.T) {
+	unsafeOnly(t)
+	c := New(OnMissing(MissingDefer))
+	built := map[string]bool{}
+	factory := func(name string) FactoryFunc {
+		return func(Keeper) (interface{}, error) {
+			built[name] = true
+			return &HelloSrv{word: name}, nil
+		}
+	}
+	c.RegisterFactory(factory("server"), Name("server"))
+	c.RegisterFactory(factory("migrations"), Name("migrations"))
+	ctl, other := new(HelloCtl), new(HelloCtl)
+	c.Register(ctl, Name("helloCtl"))
+	c.Register(other, Name("otherCtl"))
+	c.RegisterFactory(factory("helloService"), Name("helloService"))
+
+	if err := c.BuildFor("server", "helloCtl"); err != nil {
+		t.Fatal(err)
+	}
+	if !built["server"] || built["migrations"] {
+		t.Fatalf("built %v, want server only", built)
+	}
+	if ctl.helloSrv.word != "helloService" {
+		t.Fatal("deferred dependency of a root should be injected")
+	}
+	if other.helloSrv.word != "" {
+		t.Fatal("deferred dependency outside of the roots should stay deferred")
+	}
+	if err := c.BuildFor("unknown"); err == nil {
+		t.Fatal("expected error for an unregistered root")
+	}
+}
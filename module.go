@@ -0,0 +1,44 @@
+package keeper
+
+import "fmt"
+
+// Module installs the beans of a feature into a container.
+type Module interface {
+	Install(k Keeper) error
+}
+
+// ModuleFunc is a func satisfying Module.
+type ModuleFunc func(k Keeper) error
+
+func (f ModuleFunc) Install(k Keeper) error { return f(k) }
+
+// Install installs modules in order, and stops at the first failure.
+func (c *Container) Install(modules ...Module) error {
+	for _, m := range modules {
+		if err := m.Install(c); err != nil {
+			return fmt.Errorf("failed to install module: %v", err)
+		}
+	}
+	return nil
+}
+
+// _pluginSymbol is the symbol LoadPlugin looks up in plugins.
+const _pluginSymbol = "KeeperModule"
+
+// moduleOf returns the Module a plugin exports as KeeperModule, which is
+// either a func(keeper.Keeper) error or a variable holding a Module.
+func moduleOf(sym interface{}) (Module, error) {
+	switch m := sym.(type) {
+	case func(Keeper) error:
+		return ModuleFunc(m), nil
+	case *func(Keeper) error:
+		return ModuleFunc(*m), nil
+	case *ModuleFunc:
+		return *m, nil
+	case *Module:
+		return *m, nil
+	case Module:
+		return m, nil
+	}
+	return nil, fmt.Errorf("%s is %T, not a keeper.Module", _pluginSymbol, sym)
+}
@@ -0,0 +1,42 @@
+package keeper
+
+import (
+	"errors"
+	"testing"
+)
+
+type helloModule struct{}
+
+func (helloModule) Install(k Keeper) error {
+	return k.Register(&HelloSrv{}, Name("helloService"))
+}
+
+func TestContainer_Install(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	err := c.Install(helloModule{}, ModuleFunc(func(k Keeper) error {
+		return k.Register(new(HelloCtl), Name("helloCtl"))
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Find("helloCtl") == nil {
+		t.Fatal("module beans should be registered")
+	}
+	if err := c.Install(ModuleFunc(func(Keeper) error { return errors.New("boom") })); err == nil {
+		t.Fatal("expected error of the module")
+	}
+}
+
+func TestModuleOf(t *testing.T) {
+	fn := func(k Keeper) error { return nil }
+	var m Module = helloModule{}
+	for _, sym := range []interface{}{fn, &fn, &m, helloModule{}} {
+		if _, err := moduleOf(sym); err != nil {
+			t.Errorf("moduleOf(%T): %v", sym, err)
+		}
+	}
+	if _, err := moduleOf(42); err == nil {
+		t.Fatal("expected error for a symbol which isn't a module")
+	}
+}
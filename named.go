@@ -0,0 +1,98 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	namedMu sync.Mutex
+	named   = make(map[string]*Container)
+)
+
+// NewNamed creates a container like New, and makes it discoverable by Lookup
+// under name until it's closed, for processes hosting several applications,
+// each with its own container. The containers are isolated: beans, options
+// and lifecycles aren't shared, unless WithParent says so.
+func NewNamed(name string, opts ...Option) (Keeper, error) {
+	if name == "" {
+		return nil, fmt.Errorf("cannot use empty container name")
+	}
+	namedMu.Lock()
+	defer namedMu.Unlock()
+	if _, exist := named[name]; exist {
+		return nil, fmt.Errorf("container %s already exists", name)
+	}
+	c := New(opts...).(*Container)
+	c.instance = name
+	named[name] = c
+	return c, nil
+}
+
+// Lookup returns the container created by NewNamed under name, nil if there
+// is none or it's closed.
+func Lookup(name string) Keeper {
+	namedMu.Lock()
+	defer namedMu.Unlock()
+	if c, ok := named[name]; ok {
+		return c
+	}
+	return nil
+}
+
+// NamedContainers returns the names of the containers created by NewNamed
+// which aren't closed, sorted.
+func NamedContainers() []string {
+	namedMu.Lock()
+	defer namedMu.Unlock()
+	names := make([]string, 0, len(named))
+	for name := range named {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StartNamed starts the named containers in the order of their names. A
+// container failing to start doesn't prevent the others from starting, the
+// failures are returned together.
+func StartNamed(ctx context.Context) error {
+	var errs multiError
+	for _, name := range NamedContainers() {
+		if k := Lookup(name); k != nil {
+			if err := k.Start(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("container %s: %w", name, err))
+			}
+		}
+	}
+	return errs.errOrNil()
+}
+
+// CloseNamed closes the named containers in the reverse order of their
+// names, and returns the failures together.
+func CloseNamed() error {
+	var errs multiError
+	names := NamedContainers()
+	for i := len(names) - 1; i >= 0; i-- {
+		if k := Lookup(names[i]); k != nil {
+			if err := k.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("container %s: %w", names[i], err))
+			}
+		}
+	}
+	return errs.errOrNil()
+}
+
+// forget removes the container from the named containers once closed.
+func (c *Container) forget() {
+	if c.instance == "" {
+		return
+	}
+	namedMu.Lock()
+	defer namedMu.Unlock()
+	if named[c.instance] == c {
+		delete(named, c.instance)
+	}
+}
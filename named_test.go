@@ -0,0 +1,36 @@
+package keeper
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestNewNamed(t *testing.T) {
+	ingest, err := NewNamed("ingest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	edge, _ := NewNamed("edge")
+	defer CloseNamed()
+	if _, err := NewNamed("ingest"); err == nil {
+		t.Fatal("expected a duplicate name to be refused")
+	}
+	ingest.Register(&HelloSrv{}, Name("helloService"))
+	if Lookup("ingest") != ingest || Lookup("edge").Find("helloService") != nil {
+		t.Fatal("named containers should be isolated")
+	}
+	if got := NamedContainers(); !reflect.DeepEqual(got, []string{"edge", "ingest"}) {
+		t.Fatalf("got %v", got)
+	}
+	if err := StartNamed(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	edge.Close()
+	if Lookup("edge") != nil {
+		t.Fatal("closed container should be forgotten")
+	}
+	if _, err := NewNamed("edge"); err != nil {
+		t.Fatalf("name of a closed container should be reusable, got %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package keeper
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ErrDisabled is returned by the methods of nil objects, which stand for
+// the features left out of a build.
+var ErrDisabled = errors.New("feature is disabled")
+
+var (
+	nilObjectsMu sync.Mutex
+	nilObjects   = make(map[reflect.Type]interface{})
+)
+
+// RegisterNilObject registers obj as the nil object of the interface iface
+// points to: its methods do nothing, returning zero values and ErrDisabled
+// as their error. It's meant to be called from init functions, like those
+// cmd/keepergen -mode nilobjects generates, and panics when called twice for
+// an interface or given something else than a pointer to interface.
+func RegisterNilObject(iface interface{}, obj interface{}) {
+	typ := interfaceOf("RegisterNilObject", iface)
+	if obj == nil || !reflect.TypeOf(obj).Implements(typ) {
+		panic(fmt.Sprintf("keeper: RegisterNilObject given %T, which doesn't implement %v", obj, typ))
+	}
+	nilObjectsMu.Lock()
+	defer nilObjectsMu.Unlock()
+	if _, dup := nilObjects[typ]; dup {
+		panic(fmt.Sprintf("keeper: RegisterNilObject called twice for %v", typ))
+	}
+	nilObjects[typ] = obj
+}
+
+// NilObjectFor is an Option that injects the nil object registered for the
+// interface iface points to into the fields of that interface whose bean is
+// missing, like when the module of an optional feature isn't installed. A
+// default registered by RegisterDefaultFor takes precedence.
+//
+//	c := keeper.New(keeper.NilObjectFor((*Billing)(nil)))
+//
+// It panics if no nil object is registered for the interface.
+func NilObjectFor(iface interface{}) Option {
+	return optionFunc(func(c *Container) {
+		typ := interfaceOf("NilObjectFor", iface)
+		nilObjectsMu.Lock()
+		obj, ok := nilObjects[typ]
+		nilObjectsMu.Unlock()
+		if !ok {
+			panic(fmt.Sprintf("keeper: no nil object registered for %v, generate one with keepergen -mode nilobjects", typ))
+		}
+		if c.nilObjects == nil {
+			c.nilObjects = make(map[reflect.Type]interface{})
+		}
+		c.nilObjects[typ] = obj
+	})
+}
+
+// interfaceOf returns the interface iface points to, panicking with the name
+// of fn otherwise.
+func interfaceOf(fn string, iface interface{}) reflect.Type {
+	typ := reflect.TypeOf(iface)
+	if typ == nil || typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Interface {
+		panic(fmt.Sprintf("keeper: %s must be given a pointer to interface, got %v", fn, typ))
+	}
+	return typ.Elem()
+}
@@ -0,0 +1,62 @@
+package keeper
+
+import (
+	"errors"
+	"testing"
+)
+
+type billing interface {
+	Charge(cents int64) (string, error)
+}
+
+type nilBilling struct{}
+
+func (nilBilling) Charge(int64) (r0 string, r1 error) {
+	r1 = ErrDisabled
+	return
+}
+
+func init() {
+	RegisterNilObject((*billing)(nil), nilBilling{})
+}
+
+type checkout struct {
+	billing billing `name:"billing"`
+}
+
+func TestNilObjectFor(t *testing.T) {
+	unsafeOnly(t)
+	c := New(NilObjectFor((*billing)(nil)))
+	co := new(checkout)
+	if err := c.Register(co, Name("checkout")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := co.billing.Charge(100); !errors.Is(err, ErrDisabled) {
+		t.Fatalf("got %v, want the nil object", err)
+	}
+	if err := c.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	co = new(checkout)
+	if err := c.Register(co, Name("checkout")); err != nil || co.billing == nil {
+		t.Fatalf("nil object should survive Reset, got %v", err)
+	}
+}
+
+func TestNilObjectPanics(t *testing.T) {
+	for name, fn := range map[string]func(){
+		"twice":          func() { RegisterNilObject((*billing)(nil), nilBilling{}) },
+		"unimplemented":  func() { RegisterNilObject((*billing)(nil), new(HelloSrv)) },
+		"not registered": func() { New(NilObjectFor((*Initializer)(nil))) },
+		"not interface":  func() { New(NilObjectFor(new(HelloSrv))) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s should panic", name)
+				}
+			}()
+			fn()
+		}()
+	}
+}
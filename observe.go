@@ -0,0 +1,36 @@
+package keeper
+
+// Injection is a dependency injected into a field of a bean.
+type Injection struct {
+	Bean   string // empty for Provider
+	Field  string
+	Source string // name of the injected bean
+}
+
+// OnInject is an Option that invokes hook after each dependency is injected.
+// Hooks run synchronously during Register, in the order they were given.
+func OnInject(hook func(Injection)) Option {
+	return optionFunc(func(c *Container) {
+		c.injectHooks = append(c.injectHooks, hook)
+	})
+}
+
+func (c *Container) notifyInject(in Injection) {
+	for _, hook := range c.injectHooks {
+		hook(in)
+	}
+}
+
+// OnDestroy is an Option that invokes hook with the name of each Disposer
+// bean right before Close destroys it.
+func OnDestroy(hook func(name string)) Option {
+	return optionFunc(func(c *Container) {
+		c.destroyHooks = append(c.destroyHooks, hook)
+	})
+}
+
+func (c *Container) notifyDestroy(name string) {
+	for _, hook := range c.destroyHooks {
+		hook(name)
+	}
+}
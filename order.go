@@ -0,0 +1,34 @@
+package keeper
+
+import "sort"
+
+// Ordering is the order Range, Beans, Stats, Jobs and DumpState enumerate
+// the beans in. All returns a map, whose iteration order is random: iterate
+// with Range for a deterministic order.
+type Ordering int
+
+const (
+	// RegistrationOrder enumerates the beans in the order they were registered.
+	RegistrationOrder Ordering = iota
+	// NameOrder enumerates the beans sorted by name, which is stable across
+	// changes of the registration order, at the cost of a sort.
+	NameOrder
+)
+
+// WithOrdering is an Option that sets the Ordering of the beans, RegistrationOrder by default.
+func WithOrdering(ordering Ordering) Option {
+	return optionFunc(func(c *Container) {
+		c.ordering = ordering
+	})
+}
+
+// enumeration returns the names of the beans in the Ordering of the container.
+// It's called with mu held, the names must not be modified.
+func (c *Container) enumeration() []string {
+	if c.ordering == NameOrder {
+		names := append([]string(nil), c.order...)
+		sort.Strings(names)
+		return names
+	}
+	return c.order[:len(c.order):len(c.order)] // order is only appended to
+}
@@ -0,0 +1,33 @@
+package keeper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestContainer_Ordering(t *testing.T) {
+	names := []string{"zeta", "alpha", "mu", "beta"}
+	for _, tc := range []struct {
+		ordering Ordering
+		want     []string
+	}{
+		{RegistrationOrder, names},
+		{NameOrder, []string{"alpha", "beta", "mu", "zeta"}},
+	} {
+		c := New(WithOrdering(tc.ordering))
+		for _, name := range names {
+			c.Register(&HelloSrv{}, Name(name))
+		}
+		var ranged, described []string
+		c.Range(func(name string, _ interface{}) bool {
+			ranged = append(ranged, name)
+			return true
+		})
+		for _, info := range c.Beans() {
+			described = append(described, info.Name)
+		}
+		if !reflect.DeepEqual(ranged, tc.want) || !reflect.DeepEqual(described, tc.want) {
+			t.Fatalf("ordering %d: got %v and %v, want %v", tc.ordering, ranged, described, tc.want)
+		}
+	}
+}
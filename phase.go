@@ -0,0 +1,89 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+)
+
+// The phases run by the container itself: Start runs PhaseStart once the
+// Starter beans have started, Close runs PhaseStop before destroying beans.
+const (
+	PhaseStart = "start"
+	PhaseStop  = "stop"
+)
+
+// Phase is a RegisterOption that subscribes fn to the named lifecycle phase,
+// like "warmup" or "drain", which is run by RunPhase. A bean may subscribe
+// several funcs to a phase, they run in order.
+//
+//	c.Register(cache, keeper.Name("cache"), keeper.Phase("warmup", cache.Preload))
+func Phase(phase string, fn func(ctx context.Context) error) RegisterOption {
+	return registerOptionFunc(func(options *registerOptions) {
+		if options.Phases == nil {
+			options.Phases = make(map[string][]func(context.Context) error)
+		}
+		options.Phases[phase] = append(options.Phases[phase], fn)
+	})
+}
+
+// RunPhase runs the funcs subscribed to phase in dependency order, so a
+// bean's dependencies run first, and stops at the first failure. PhaseStop
+// runs in reverse, dependents first, and keeps going after failures so that
+// every bean gets to stop; their errors are joined.
+func (c *Container) RunPhase(ctx context.Context, phase string) error {
+	names := c.dependencyOrder()
+	if phase == PhaseStop {
+		for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+			names[i], names[j] = names[j], names[i]
+		}
+	}
+	var errs multiError
+	for _, name := range names {
+		c.mu.RLock()
+		fns := c.opts[name].Phases[phase]
+		c.mu.RUnlock()
+		for _, fn := range fns {
+			if err := c.runPhase(ctx, name, fn); err != nil {
+				c.recordError(name, err)
+				err = fmt.Errorf("failed to run %s phase of %s: %v", phase, name, err)
+				if phase != PhaseStop {
+					return err
+				}
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errs.errOrNil()
+}
+
+func (c *Container) runPhase(ctx context.Context, name string, fn func(context.Context) error) error {
+	defer c.dumpOnPanic(name)
+	return fn(ctx)
+}
+
+// dependencyOrder returns the bean names with dependencies before their
+// dependents, in registration order otherwise. Cycles are broken at the bean
+// registered first.
+func (c *Container) dependencyOrder() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	names := make([]string, 0, len(c.order))
+	visited := make(map[string]bool, len(c.order))
+	var visit func(name string)
+	visit = func(name string) {
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+		for _, dep := range c.deps[name] {
+			if _, ok := c.opts[dep]; ok { // groups have no options
+				visit(dep)
+			}
+		}
+		names = append(names, name)
+	}
+	for _, name := range c.order {
+		visit(name)
+	}
+	return names
+}
@@ -0,0 +1,68 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestContainer_RunPhase(t *testing.T) {
+	unsafeOnly(t)
+	var ran []string
+	record := func(name string) func(context.Context) error {
+		return func(context.Context) error {
+			ran = append(ran, name)
+			return nil
+		}
+	}
+	c := New(OnMissing(MissingDefer))
+	c.Register(new(HelloCtl), Name("helloCtl"), Phase("warmup", record("helloCtl")), Phase(PhaseStop, record("stopCtl")))
+	c.Register(&HelloSrv{}, Name("helloService"), Phase("warmup", record("helloService")), Phase(PhaseStop, record("stopSrv")))
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.RunPhase(context.Background(), "warmup"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// helloCtl depends on helloService, registered later
+	if got := strings.Join(ran, " "); got != "helloService helloCtl stopCtl stopSrv" {
+		t.Fatalf("got %s", got)
+	}
+}
+
+func TestContainer_RunPhaseError(t *testing.T) {
+	c := New()
+	c.Register(&HelloSrv{}, Name("helloService"), Phase("warmup", func(context.Context) error {
+		return errors.New("cold")
+	}))
+	err := c.RunPhase(context.Background(), "warmup")
+	if err == nil || err.Error() != "failed to run warmup phase of helloService: cold" {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestContainer_RunPhaseStopErrors(t *testing.T) {
+	unsafeOnly(t)
+	var stopped []string
+	stop := func(name string, err error) func(context.Context) error {
+		return func(context.Context) error {
+			stopped = append(stopped, name)
+			return err
+		}
+	}
+	c := New()
+	c.Register(&HelloSrv{}, Name("helloService"), Phase(PhaseStop, stop("helloService", errors.New("stuck"))))
+	c.Register(new(HelloCtl), Name("helloCtl"), Phase(PhaseStop, stop("helloCtl", errors.New("busy"))))
+	err := c.Close()
+	if got := strings.Join(stopped, " "); got != "helloCtl helloService" {
+		t.Fatalf("got %s, every bean should stop", got)
+	}
+	want := "failed to run stop phase of helloCtl: busy; failed to run stop phase of helloService: stuck"
+	if err == nil || err.Error() != want {
+		t.Fatalf("got %v, want %s", err, want)
+	}
+}
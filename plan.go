@@ -0,0 +1,83 @@
+package keeper
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// fieldClass is how a field is injected, set by its type and tags.
+type fieldClass uint8
+
+const (
+	untaggedField     fieldClass = iota // injected by its name with FieldNames only
+	taggedField                         // tagged with name, value, ctx or config
+	factoryFieldClass                   // a Factory[T]
+	loggerField                         // an untagged *slog.Logger, injected by WithSlog
+	lazyFieldClass                      // a Lazy[T], tagged with name or not
+	chainFieldClass                     // a Chain[H]
+)
+
+// plannedField is a field of a struct, with its tag looked up once.
+type plannedField struct {
+	reflect.StructField
+	class  fieldClass
+	tagKey string // the tag the field is injected by, in the order loadField checks them
+	tag    string
+}
+
+// injectionPlan is the fields of a struct type, in declaration order.
+type injectionPlan struct {
+	fields []plannedField
+}
+
+var (
+	plansMu sync.Mutex   // serializes writers of plans
+	plans   atomic.Value // map[reflect.Type]*injectionPlan, immutable once published
+)
+
+// planOf returns the injectionPlan of the struct type typ. Plans are cached
+// like tags, so providing the same type over and over, as middlewares do
+// per request, only looks it up; a plan must not be modified.
+func planOf(typ reflect.Type) *injectionPlan {
+	cache, _ := plans.Load().(map[reflect.Type]*injectionPlan)
+	if plan, ok := cache[typ]; ok {
+		return plan
+	}
+	plan := newInjectionPlan(typ)
+	plansMu.Lock()
+	defer plansMu.Unlock()
+	cache, _ = plans.Load().(map[reflect.Type]*injectionPlan)
+	cp := make(map[reflect.Type]*injectionPlan, len(cache)+1)
+	for k, v := range cache {
+		cp[k] = v
+	}
+	cp[typ] = plan
+	plans.Store(cp)
+	return plan
+}
+
+func newInjectionPlan(typ reflect.Type) *injectionPlan {
+	plan := &injectionPlan{fields: make([]plannedField, typ.NumField())}
+	for i := range plan.fields {
+		f := plannedField{StructField: typ.Field(i)}
+		for _, key := range []string{_valueTag, _ctxTag, _configTag, _nameTag} {
+			if tag, ok := f.Tag.Lookup(key); ok {
+				f.class, f.tagKey, f.tag = taggedField, key, tag
+				break
+			}
+		}
+		switch {
+		case f.Type.Implements(_factoryFieldType):
+			f.class = factoryFieldClass
+		case f.Type.Implements(_lazyFieldType):
+			f.class = lazyFieldClass
+		case f.Type.Implements(_chainFieldType):
+			f.class = chainFieldClass
+		case f.class == untaggedField && f.Type == _slogType:
+			f.class = loggerField
+		}
+		plan.fields[i] = f
+	}
+	return plan
+}
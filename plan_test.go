@@ -0,0 +1,63 @@
+package keeper
+
+import (
+	"log/slog"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestPlanOf(t *testing.T) {
+	type planned struct {
+		srv     *HelloSrv `name:"helloSrv"`
+		port    int       `value:"port"`
+		request string    `ctx:"request"`
+		section string    `config:"section"`
+		logger  *slog.Logger
+		factory Factory[*HelloSrv]
+		plain   string
+	}
+	typ := reflect.TypeOf(planned{})
+	plan := planOf(typ)
+	if planOf(typ) != plan {
+		t.Error("plan should be cached")
+	}
+	want := []struct {
+		class  fieldClass
+		tagKey string
+	}{
+		{taggedField, _nameTag},
+		{taggedField, _valueTag},
+		{taggedField, _ctxTag},
+		{taggedField, _configTag},
+		{loggerField, ""},
+		{factoryFieldClass, ""},
+		{untaggedField, ""},
+	}
+	for i, f := range plan.fields {
+		if f.class != want[i].class || f.tagKey != want[i].tagKey {
+			t.Errorf("%s: got %d %q, want %d %q", f.Name, f.class, f.tagKey, want[i].class, want[i].tagKey)
+		}
+	}
+}
+
+func TestProviderConcurrent(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	c.Register(&HelloSrv{word: "hello"}, Name("helloService"))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				ctl := new(HelloCtl)
+				if err := c.Provider(ctl); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,41 @@
+//go:build (linux || darwin || freebsd) && cgo
+// +build linux darwin freebsd
+// +build cgo
+
+package keeper
+
+import (
+	"fmt"
+	"plugin"
+)
+
+// LoadPlugin opens the Go plugin at path and installs the Module it exports
+// as KeeperModule:
+//
+//	// built with go build -buildmode=plugin
+//	package main
+//
+//	func KeeperModule(k keeper.Keeper) error {
+//		return k.Register(new(Feature), keeper.Name("feature"))
+//	}
+//
+// A plugin is loaded once per process, loading it into a second container
+// installs the module again.
+func (c *Container) LoadPlugin(path string) error {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to load plugin %s: %v", path, err)
+	}
+	sym, err := p.Lookup(_pluginSymbol)
+	if err != nil {
+		return fmt.Errorf("failed to load plugin %s: %v", path, err)
+	}
+	m, err := moduleOf(sym)
+	if err != nil {
+		return fmt.Errorf("failed to load plugin %s: %v", path, err)
+	}
+	if err := c.Install(m); err != nil {
+		return fmt.Errorf("failed to load plugin %s: %v", path, err)
+	}
+	return nil
+}
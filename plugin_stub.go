@@ -0,0 +1,11 @@
+//go:build !((linux || darwin || freebsd) && cgo)
+// +build !linux,!darwin,!freebsd !cgo
+
+package keeper
+
+import "fmt"
+
+// LoadPlugin is only supported on linux, darwin and freebsd with cgo.
+func (c *Container) LoadPlugin(path string) error {
+	return fmt.Errorf("failed to load plugin %s: plugins aren't supported on this platform", path)
+}
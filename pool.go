@@ -0,0 +1,164 @@
+package keeper
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+)
+
+// WithParent is an Option that resolves the names the container doesn't
+// have from parent, by Find and by injection, so the container overlays the
+// graph of parent: its beans override the ones of parent under the same
+// name. The beans of parent keep their own dependencies.
+func WithParent(parent Keeper) Option {
+	return optionFunc(func(c *Container) {
+		if p, ok := parent.(*Container); ok {
+			c.parent = p.lookup // doesn't panic in DevMode
+			return
+		}
+		c.parent = parent.Find
+	})
+}
+
+// OverlayFunc registers the beans of tenant into its overlay k, like its
+// feature flags and quotas.
+type OverlayFunc func(tenant string, k Keeper) error
+
+// ContainerPool derives per-tenant containers from a shared base graph,
+// which must not change once the pool is used. Overlays only hold the beans
+// of their tenant, so they are cheap to build.
+type ContainerPool struct {
+	base       Keeper
+	overlay    OverlayFunc
+	maxTenants int
+
+	mu       sync.Mutex
+	tenants  map[string]*list.Element // of *tenantOverlay
+	recent   *list.List               // most recently used first
+	building map[string]*tenantOverlay
+}
+
+type tenantOverlay struct {
+	tenant string
+	keeper Keeper
+	err    error
+	built  chan struct{}
+}
+
+// PoolOption configures a ContainerPool.
+type PoolOption func(*ContainerPool)
+
+// MaxTenants is a PoolOption that keeps at most n overlays, the least
+// recently used one being closed to make room. The default is unbounded.
+func MaxTenants(n int) PoolOption {
+	return func(p *ContainerPool) {
+		p.maxTenants = n
+	}
+}
+
+// NewContainerPool returns a pool of overlays of base built by overlay.
+func NewContainerPool(base Keeper, overlay OverlayFunc, opts ...PoolOption) *ContainerPool {
+	p := &ContainerPool{
+		base:     base,
+		overlay:  overlay,
+		tenants:  make(map[string]*list.Element),
+		recent:   list.New(),
+		building: make(map[string]*tenantOverlay),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Base returns the base container of the pool.
+func (p *ContainerPool) Base() Keeper {
+	return p.base
+}
+
+// Get returns the overlay of tenant, building it on first use. Concurrent
+// calls for a tenant wait for a single build, a failed build is retried by
+// the next call.
+func (p *ContainerPool) Get(tenant string) (Keeper, error) {
+	p.mu.Lock()
+	if e, ok := p.tenants[tenant]; ok {
+		p.recent.MoveToFront(e)
+		p.mu.Unlock()
+		return e.Value.(*tenantOverlay).keeper, nil
+	}
+	o, ok := p.building[tenant]
+	if !ok {
+		o = &tenantOverlay{tenant: tenant, built: make(chan struct{})}
+		p.building[tenant] = o
+		p.mu.Unlock()
+		p.build(o)
+	} else {
+		p.mu.Unlock()
+	}
+	<-o.built
+	return o.keeper, o.err
+}
+
+func (p *ContainerPool) build(o *tenantOverlay) {
+	k := New(WithParent(p.base))
+	err := p.applyOverlay(o.tenant, k)
+	if err != nil {
+		k.Close()
+		o.err = fmt.Errorf("failed to build overlay of tenant %s: %v", o.tenant, err)
+	} else {
+		o.keeper = k
+	}
+
+	var evicted []*tenantOverlay
+	p.mu.Lock()
+	delete(p.building, o.tenant)
+	if err == nil {
+		p.tenants[o.tenant] = p.recent.PushFront(o)
+		for p.maxTenants > 0 && p.recent.Len() > p.maxTenants {
+			evicted = append(evicted, p.remove(p.recent.Back()))
+		}
+	}
+	p.mu.Unlock()
+	close(o.built)
+	for _, e := range evicted {
+		e.keeper.Close()
+	}
+}
+
+// applyOverlay runs the OverlayFunc, turning its panic into an error so that
+// the callers waiting for the build are released.
+func (p *ContainerPool) applyOverlay(tenant string, k Keeper) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = panicError{value: r}
+		}
+	}()
+	return p.overlay(tenant, k)
+}
+
+// Evict closes and drops the overlay of tenant, the next Get builds it again.
+func (p *ContainerPool) Evict(tenant string) {
+	p.mu.Lock()
+	e, ok := p.tenants[tenant]
+	var o *tenantOverlay
+	if ok {
+		o = p.remove(e)
+	}
+	p.mu.Unlock()
+	if o != nil {
+		o.keeper.Close()
+	}
+}
+
+func (p *ContainerPool) remove(e *list.Element) *tenantOverlay {
+	o := p.recent.Remove(e).(*tenantOverlay)
+	delete(p.tenants, o.tenant)
+	return o
+}
+
+// Len returns the number of overlays in the pool.
+func (p *ContainerPool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.recent.Len()
+}
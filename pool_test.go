@@ -0,0 +1,129 @@
+package keeper
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type quota struct {
+	limit int
+}
+
+type tenantService struct {
+	quota *quota    `name:"quota"`
+	srv   *HelloSrv `name:"helloService"`
+}
+
+func newTenantPool(opts ...PoolOption) (*ContainerPool, *int) {
+	base := New()
+	base.Register(&HelloSrv{word: "shared"}, Name("helloService"))
+	base.Register(&quota{limit: 10}, Name("quota"))
+	builds := new(int)
+	return NewContainerPool(base, func(tenant string, k Keeper) error {
+		*builds++
+		if tenant == "broken" {
+			return errors.New("no such tenant")
+		}
+		if tenant == "premium" {
+			k.Register(&quota{limit: 100}, Name("quota"))
+		}
+		return k.Register(new(tenantService), Name("service"))
+	}, opts...), builds
+}
+
+func TestContainerPool_Get(t *testing.T) {
+	unsafeOnly(t)
+	pool, builds := newTenantPool()
+	premium, err := pool.Get("premium")
+	if err != nil {
+		t.Fatal(err)
+	}
+	free, _ := pool.Get("free")
+	ps, fs := premium.Find("service").(*tenantService), free.Find("service").(*tenantService)
+	if ps.quota.limit != 100 || fs.quota.limit != 10 || ps.srv != fs.srv {
+		t.Fatalf("got %+v and %+v", ps, fs)
+	}
+	if again, _ := pool.Get("premium"); again != premium || *builds != 2 {
+		t.Fatal("overlays should be built once")
+	}
+	if _, err := pool.Get("broken"); err == nil {
+		t.Fatal("expected error of the overlay")
+	}
+	if pool.Base().Find("service") != nil {
+		t.Fatal("overlays should not change the base")
+	}
+}
+
+func TestContainerPool_Evict(t *testing.T) {
+	unsafeOnly(t)
+	pool, builds := newTenantPool(MaxTenants(2))
+	pool.Get("a")
+	pool.Get("b")
+	pool.Get("a")
+	pool.Get("c") // evicts b, the least recently used
+	if pool.Len() != 2 {
+		t.Fatalf("got %d overlays", pool.Len())
+	}
+	pool.Get("a")
+	pool.Get("b")
+	if *builds != 4 {
+		t.Fatalf("got %d builds, b should be rebuilt", *builds)
+	}
+	pool.Evict("b")
+	if pool.Len() != 1 {
+		t.Fatalf("got %d overlays after Evict", pool.Len())
+	}
+}
+
+func TestContainerPool_Concurrent(t *testing.T) {
+	base := New()
+	var mu sync.Mutex
+	builds := 0
+	pool := NewContainerPool(base, func(string, Keeper) error {
+		mu.Lock()
+		builds++
+		mu.Unlock()
+		return nil
+	})
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pool.Get("tenant")
+		}()
+	}
+	wg.Wait()
+	if builds != 1 {
+		t.Fatalf("got %d builds", builds)
+	}
+}
+
+func TestContainerPool_OverlayPanic(t *testing.T) {
+	panics := true
+	p := NewContainerPool(New(), func(tenant string, k Keeper) error {
+		if panics {
+			panic("tenant config is corrupt")
+		}
+		return nil
+	})
+	done := make(chan error)
+	go func() {
+		_, err := p.Get("acme")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("expected the panic of the overlay to fail Get")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Get hangs after the overlay panicked")
+	}
+	panics = false
+	if _, err := p.Get("acme"); err != nil {
+		t.Fatalf("the build should be retried: %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package keeper
+
+import "strings"
+
+// WithProfiles is an Option that activates profiles, like "prod" or
+// "staging", for fields tagged with the ifprofile option:
+//
+//	type Service struct {
+//		tracer Tracer `name:"tracer,ifprofile=prod"`
+//		sink   Sink   `name:"kafkaSink,ifprofile='prod|staging',default=stdoutSink"`
+//	}
+//
+// A field whose profiles are all inactive is left zero, or gets the default.
+func WithProfiles(profiles ...string) Option {
+	return optionFunc(func(c *Container) {
+		if c.profiles == nil {
+			c.profiles = make(map[string]bool)
+		}
+		for _, p := range profiles {
+			c.profiles[p] = true
+		}
+	})
+}
+
+// profileActive reports whether the field of spec should be injected.
+func (c *Container) profileActive(spec tagSpec) bool {
+	cond, ok := spec.option(_ifProfileOption)
+	if !ok {
+		return true
+	}
+	for _, p := range strings.Split(cond, "|") {
+		if c.profiles[p] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package keeper
+
+import "testing"
+
+type profiledService struct {
+	tracer *HelloSrv `name:"tracer,ifprofile=prod"`
+	sink   *HelloSrv `name:"kafkaSink,ifprofile='prod|staging',default=stdoutSink"`
+	level  string    `value:"log.level,ifprofile=dev,default=info"`
+}
+
+func newProfiledContainer(profiles ...string) Keeper {
+	c := New(WithProfiles(profiles...), WithConfig(&mapSource{values: map[string]string{"log.level": "debug"}}))
+	c.Register(&HelloSrv{word: "tracer"}, Name("tracer"))
+	c.Register(&HelloSrv{word: "kafka"}, Name("kafkaSink"))
+	c.Register(&HelloSrv{word: "stdout"}, Name("stdoutSink"))
+	return c
+}
+
+func TestContainer_IfProfile(t *testing.T) {
+	unsafeOnly(t)
+	svc := new(profiledService)
+	if err := newProfiledContainer("prod").Register(svc, Name("svc")); err != nil {
+		t.Fatal(err)
+	}
+	if svc.tracer == nil || svc.sink.word != "kafka" || svc.level != "info" {
+		t.Fatalf("got %+v in prod", svc)
+	}
+
+	svc = new(profiledService)
+	if err := newProfiledContainer("dev").Register(svc, Name("svc")); err != nil {
+		t.Fatal(err)
+	}
+	if svc.tracer != nil || svc.sink.word != "stdout" || svc.level != "debug" {
+		t.Fatalf("got %+v in dev", svc)
+	}
+}
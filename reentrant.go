@@ -0,0 +1,34 @@
+package keeper
+
+import "fmt"
+
+// Beans may register other beans from AfterPropertySet, as self-registering
+// plugins do. The bean being registered is published once its initializer
+// returns, so the beans it registers are published first and can't depend on
+// it. Registering a name which is being registered, like the bean itself,
+// fails.
+
+func (c *Container) beginLoading(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.loading[name] {
+		return fmt.Errorf("register re-entrant! %s is already being registered, by its own initializer or concurrently", name)
+	}
+	if c.loading == nil {
+		c.loading = make(map[string]bool)
+	}
+	c.loading[name] = true
+	return nil
+}
+
+func (c *Container) endLoading(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.loading, name)
+}
+
+func (c *Container) isLoading(name string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.loading[name]
+}
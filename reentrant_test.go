@@ -0,0 +1,60 @@
+package keeper
+
+import (
+	"strings"
+	"testing"
+)
+
+type selfRegistering struct {
+	c    Keeper
+	name string
+	err  error
+}
+
+func (p *selfRegistering) AfterPropertySet() {
+	p.err = p.c.Register(&HelloSrv{}, Name(p.name))
+}
+
+type parentRegistering struct {
+	c   Keeper
+	err error
+}
+
+type pluginChild struct {
+	parent *parentRegistering `name:"plugin"`
+}
+
+func (p *parentRegistering) AfterPropertySet() {
+	p.err = p.c.Register(new(pluginChild), Name("child"))
+}
+
+func TestContainer_RegisterFromInitializer(t *testing.T) {
+	c := New()
+	plugin := &selfRegistering{c: c, name: "pluginHelper"}
+	if err := c.Register(plugin, Name("plugin")); err != nil || plugin.err != nil {
+		t.Fatal(err, plugin.err)
+	}
+	if names := []string{c.Beans()[0].Name, c.Beans()[1].Name}; names[0] != "pluginHelper" || names[1] != "plugin" {
+		t.Fatalf("got %v, beans registered by initializers come first", names)
+	}
+}
+
+func TestContainer_RegisterItselfFromInitializer(t *testing.T) {
+	c := New()
+	plugin := &selfRegistering{c: c, name: "plugin"}
+	if err := c.Register(plugin, Name("plugin")); err != nil {
+		t.Fatal(err)
+	}
+	if plugin.err == nil || !strings.Contains(plugin.err.Error(), "re-entrant") {
+		t.Fatalf("got %v", plugin.err)
+	}
+}
+
+func TestContainer_RegisterDependentFromInitializer(t *testing.T) {
+	c := New()
+	parent := &parentRegistering{c: c}
+	c.Register(parent, Name("plugin"))
+	if parent.err == nil || !strings.Contains(parent.err.Error(), "it's being registered") {
+		t.Fatalf("got %v", parent.err)
+	}
+}
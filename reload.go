@@ -0,0 +1,138 @@
+package keeper
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+)
+
+// HotReload rebuilds beans when the files they're built from change, for
+// development servers heavy on configuration: the rebuilt bean replaces the
+// old one by Swap, which updates the fields of its dependents, and the old
+// bean is destroyed if it's a Disposer. Files are polled, a change being a
+// new modification time or size.
+//
+//	hr := keeper.NewHotReload(c, time.Second).
+//		Watch("config/db.yaml", "db", func() (interface{}, error) { return openDB("config/db.yaml") })
+//	go hr.Run(ctx)
+type HotReload struct {
+	k        Keeper
+	interval time.Duration
+
+	mu       sync.Mutex
+	watches  []*fileWatch
+	onReload []func(ReloadEvent)
+}
+
+// ReloadEvent reports the rebuild of a bean after its file changed, Err
+// being set when the bean is kept because its rebuild or swap failed.
+type ReloadEvent struct {
+	File string
+	Bean string
+	Err  error
+}
+
+type fileWatch struct {
+	path    string
+	bean    string
+	build   func() (interface{}, error)
+	modTime time.Time
+	size    int64
+}
+
+// NewHotReload returns a HotReload of the beans of k polling every interval,
+// every second when interval isn't positive.
+func NewHotReload(k Keeper, interval time.Duration) *HotReload {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return &HotReload{k: k, interval: interval}
+}
+
+// Watch rebuilds the bean of name with build whenever the file at path
+// changes. A file may be watched for several beans, they're rebuilt in the
+// order they were watched.
+func (h *HotReload) Watch(path, name string, build func() (interface{}, error)) *HotReload {
+	w := &fileWatch{path: path, bean: name, build: build}
+	w.modTime, w.size = stat(path)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.watches = append(h.watches, w)
+	return h
+}
+
+// OnReload invokes fn after each rebuild.
+func (h *HotReload) OnReload(fn func(ReloadEvent)) *HotReload {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onReload = append(h.onReload, fn)
+	return h
+}
+
+// Run polls the files until ctx is done.
+func (h *HotReload) Run(ctx context.Context) error {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			h.Check()
+		}
+	}
+}
+
+// Check polls the files once, rebuilds the beans of the changed ones, and
+// returns what was rebuilt.
+func (h *HotReload) Check() []ReloadEvent {
+	h.mu.Lock()
+	var changed []*fileWatch
+	for _, w := range h.watches {
+		modTime, size := stat(w.path)
+		if !modTime.Equal(w.modTime) || size != w.size {
+			w.modTime, w.size = modTime, size
+			changed = append(changed, w)
+		}
+	}
+	hooks := h.onReload
+	h.mu.Unlock()
+	events := make([]ReloadEvent, 0, len(changed))
+	for _, w := range changed {
+		e := ReloadEvent{File: w.path, Bean: w.bean, Err: h.rebuild(w)}
+		for _, hook := range hooks {
+			hook(e)
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+func (h *HotReload) rebuild(w *fileWatch) error {
+	bean, err := w.build()
+	if err != nil {
+		return err
+	}
+	old, err := h.k.Swap(w.bean, bean)
+	if err != nil {
+		if d, ok := bean.(Disposer); ok && h.k.Find(w.bean) != bean { // rejected, the old bean is kept
+			d.Destroy()
+		}
+		return err
+	}
+	if d, ok := old.(Disposer); ok {
+		return d.Destroy()
+	}
+	return nil
+}
+
+// stat returns the modification time and size of the file at path, zero if
+// it doesn't exist.
+func stat(path string) (time.Time, int64) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, 0
+	}
+	return fi.ModTime(), fi.Size()
+}
@@ -0,0 +1,73 @@
+package keeper
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type greetingConfig struct {
+	word string
+}
+
+type greeter struct {
+	config *greetingConfig `name:"greetingConfig"`
+}
+
+func TestHotReload(t *testing.T) {
+	unsafeOnly(t)
+	path := filepath.Join(t.TempDir(), "greeting.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	build := func() (interface{}, error) {
+		data, err := os.ReadFile(path)
+		return &greetingConfig{word: string(data)}, err
+	}
+	c := New()
+	cfg, err := build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Register(cfg, Name("greetingConfig")); err != nil {
+		t.Fatal(err)
+	}
+	g := new(greeter)
+	if err := c.Register(g, Name("greeter")); err != nil {
+		t.Fatal(err)
+	}
+	hr := NewHotReload(c, 0).Watch(path, "greetingConfig", build)
+	if events := hr.Check(); len(events) != 0 {
+		t.Fatalf("got %v before any change", events)
+	}
+
+	if err := os.WriteFile(path, []byte("bonjour!"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	events := hr.Check()
+	if len(events) != 1 || events[0].Err != nil || events[0].Bean != "greetingConfig" {
+		t.Fatalf("got %+v", events)
+	}
+	if g.config.word != "bonjour!" {
+		t.Fatalf("dependent got %q", g.config.word)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if events := hr.Check(); len(events) != 1 || events[0].Err == nil {
+		t.Fatalf("got %+v, want the failed rebuild", events)
+	}
+	if g.config.word != "bonjour!" {
+		t.Fatal("failed rebuild should keep the bean")
+	}
+}
+
+func TestHotReload_DefaultInterval(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := NewHotReload(New(), 0).Run(ctx); err != context.Canceled {
+		t.Fatalf("got %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package keeper
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+)
+
+// ErrSealed is returned by Reset once the container is sealed.
+var ErrSealed = errors.New("container is sealed")
+
+// Seal forbids Reset, so that a container built by main can't be wiped by a
+// stray call. A container can't be unsealed. It checks the Invariant options
+// first, a violated one leaving the container unsealed.
+func (c *Container) Seal() error {
+	if err := c.checkInvariants(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sealed = true
+	return nil
+}
+
+// Reset disposes the beans like Close, unless the container is closed, then
+// clears them along with the typed factories, the defaults and whatever the
+// beans left, like errors and statistics, so the graph can be registered
+// again from scratch: the options given to New are kept, and the beans they
+// register are registered again. It's meant for REPLs and development
+// servers rebuilding their graph when files change.
+// Reset fails with ErrSealed once Seal is called.
+func (c *Container) Reset() error {
+	c.mu.Lock()
+	if c.sealed {
+		c.mu.Unlock()
+		return fmt.Errorf("cannot reset: %w", ErrSealed)
+	}
+	closed := c.closed
+	c.closed = true // Close is a no-op meanwhile
+	c.mu.Unlock()
+	var err error
+	if !closed {
+		err = c.dispose()
+	}
+	c.mu.Lock()
+	c.nodes.Store(make(map[string]interface{}))
+	c.order = nil
+	c.deps = make(map[string][]string)
+	c.opts = make(map[string]registerOptions)
+	c.typed = make(map[reflect.Type]*lazyBean)
+	c.defaults = make(map[reflect.Type]interface{})
+	c.deferred = nil
+	c.injected = nil
+	c.initPending = nil
+	c.gates = nil
+	c.optional = nil
+	c.values = nil
+	c.lastErrors = nil
+	c.failed = nil
+	c.stats = nil
+	c.fieldStats = nil
+	c.jobs = nil
+	c.started = false
+	c.closed = false
+	atomic.StoreInt32(&c.dumped, 0)
+	c.mu.Unlock()
+	c.registerBuiltins()
+	return err
+}
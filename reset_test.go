@@ -0,0 +1,30 @@
+package keeper
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestContainer_Reset(t *testing.T) {
+	c := New(WithoutCallSites())
+	client := new(closableClient)
+	c.Register(client, Name("client"))
+	if err := c.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if !client.closed || c.Find("client") != nil || len(c.Beans()) != 0 {
+		t.Fatal("Reset should dispose and clear the beans")
+	}
+	if err := c.Register(new(closableClient), Name("client")); err != nil {
+		t.Fatalf("name should be free after Reset, got %v", err)
+	}
+	if err := c.Seal(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Reset(); !errors.Is(err, ErrSealed) {
+		t.Fatalf("got %v", err)
+	}
+	if c.Find("client") == nil {
+		t.Fatal("sealed container should keep its beans")
+	}
+}
@@ -0,0 +1,247 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Scheduled is implemented by beans which run periodically once the container
+// has started, like cleanups and reports. Schedule is either a standard cron
+// expression of 5 fields, "minute hour day-of-month month day-of-week", one
+// of @hourly, @daily, @weekly, @monthly and @yearly, or "@every 5m".
+// Scheduled beans aren't run as a Worker even though they implement it.
+type Scheduled interface {
+	Schedule() string
+	Run(ctx context.Context) error
+}
+
+// JobStats reports the runs of a Scheduled bean.
+type JobStats struct {
+	Job          string        `json:"job"`
+	Schedule     string        `json:"schedule"`
+	Runs         int           `json:"runs"`
+	Failures     int           `json:"failures"` // runs which returned an error or panicked
+	Panics       int           `json:"panics"`
+	LastRun      time.Time     `json:"lastRun,omitempty"`
+	LastDuration time.Duration `json:"lastDuration"`
+	LastError    string        `json:"lastError,omitempty"`
+	Next         time.Time     `json:"next"`
+}
+
+// Jobs returns the JobStats of the Scheduled beans in the Ordering of the container.
+// It's empty until Start.
+func (c *Container) Jobs() []JobStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	jobs := make([]JobStats, 0, len(c.jobs))
+	for _, name := range c.enumeration() {
+		if s, ok := c.jobs[name]; ok {
+			jobs = append(jobs, *s)
+		}
+	}
+	return jobs
+}
+
+// scheduledJobs parses the schedules of the Scheduled beans.
+func (c *Container) scheduledJobs() (map[string]schedule, error) {
+	schedules := make(map[string]schedule)
+	for _, nb := range c.ordered() {
+		job, ok := nb.bean.(Scheduled)
+		if !ok {
+			continue
+		}
+		s, err := parseSchedule(job.Schedule())
+		if err != nil {
+			return nil, fmt.Errorf("failed to schedule %s: %v", nb.name, err)
+		}
+		schedules[nb.name] = s
+	}
+	return schedules, nil
+}
+
+// runJob runs job on its schedule until ctx is done. Runs of a job never
+// overlap: a run which lasts past the next activation delays it.
+func (c *Container) runJob(ctx context.Context, name string, job Scheduled, s schedule) {
+	next := s.next(time.Now())
+	c.mu.Lock()
+	if c.jobs == nil {
+		c.jobs = make(map[string]*JobStats)
+	}
+	c.jobs[name] = &JobStats{Job: name, Schedule: job.Schedule(), Next: next}
+	c.mu.Unlock()
+	if next.IsZero() {
+		c.logger.Printf("keeper: job %s is never scheduled by %q", name, job.Schedule())
+	}
+	for !next.IsZero() {
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		begin := time.Now()
+		err := runWorker(ctx, job)
+		elapsed := time.Since(begin)
+		next = s.next(time.Now())
+
+		c.mu.Lock()
+		stats := c.jobs[name]
+		stats.Runs++
+		stats.LastRun = begin
+		stats.LastDuration = elapsed
+		stats.LastError = ""
+		stats.Next = next
+		if err != nil {
+			stats.Failures++
+			stats.LastError = err.Error()
+			if _, ok := err.(panicError); ok {
+				stats.Panics++
+			}
+		}
+		c.mu.Unlock()
+		if err != nil && ctx.Err() == nil {
+			c.logger.Printf("keeper: job %s failed: %v", name, err)
+		}
+	}
+}
+
+// schedule computes the activations of a job.
+type schedule interface {
+	// next returns the first activation after t, the zero time if none.
+	next(t time.Time) time.Time
+}
+
+// every activates a job at a fixed interval.
+type every time.Duration
+
+func (e every) next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+var _scheduleAliases = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// parseSchedule parses the Schedule of a Scheduled bean.
+func parseSchedule(spec string) (schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %v", spec, err)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("invalid schedule %q: interval must be positive", spec)
+		}
+		return every(d), nil
+	}
+	if alias, ok := _scheduleAliases[spec]; ok {
+		spec = alias
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q: expected 5 fields, got %d", spec, len(fields))
+	}
+	var cs cronSchedule
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	sets := [5]*uint64{&cs.minute, &cs.hour, &cs.dom, &cs.month, &cs.dow}
+	for i, field := range fields {
+		set, err := parseCronField(field, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %v", spec, err)
+		}
+		*sets[i] = set
+	}
+	if cs.dow&(1<<7) != 0 { // 7 is Sunday too
+		cs.dow |= 1
+	}
+	cs.anyDom = fields[2] == "*"
+	cs.anyDow = fields[4] == "*"
+	return &cs, nil
+}
+
+// parseCronField parses a comma separated list of "*", "n" or "n-m", each
+// with an optional "/step", into a set of bits.
+func parseCronField(field string, min, max int) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, step := part, 1
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			rng, step = part[:i], n
+		}
+		lo, hi := min, max
+		if rng != "*" {
+			bounds := strings.SplitN(rng, "-", 2)
+			var err error
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid value in %q", part)
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("invalid value in %q", part)
+				}
+			} else if step > 1 {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q is out of range %d-%d", part, min, max)
+		}
+		for n := lo; n <= hi; n += step {
+			set |= 1 << uint(n)
+		}
+	}
+	return set, nil
+}
+
+// cronSchedule activates a job at the minutes matching all its fields. As
+// with cron, a day matches if either the day of month or the day of week
+// does, unless one of them is "*".
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	anyDom, anyDow                bool
+}
+
+func (cs *cronSchedule) next(t time.Time) time.Time {
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, t.Location())
+	// a valid schedule matches within 4 years, Feb 29 being the rarest day
+	for limit := t.AddDate(5, 0, 0); t.Before(limit); {
+		switch {
+		case cs.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !cs.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case cs.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case cs.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{} // never, like on the 31st of February
+}
+
+func (cs *cronSchedule) dayMatches(t time.Time) bool {
+	dom := cs.dom&(1<<uint(t.Day())) != 0
+	dow := cs.dow&(1<<uint(t.Weekday())) != 0
+	if cs.anyDom || cs.anyDow {
+		return dom && dow
+	}
+	return dom || dow
+}
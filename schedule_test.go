@@ -0,0 +1,104 @@
+package keeper
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type reportJob struct {
+	runs int32
+}
+
+func (j *reportJob) Schedule() string { return "@every 5ms" }
+
+func (j *reportJob) Run(ctx context.Context) error {
+	if atomic.AddInt32(&j.runs, 1) == 1 {
+		panic("report store is down")
+	}
+	return nil
+}
+
+func TestContainer_Scheduled(t *testing.T) {
+	c := New()
+	job := new(reportJob)
+	c.Register(job, Name("report"))
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&job.runs) < 3 {
+		if time.Now().After(deadline) {
+			t.Fatal("job is not rescheduled after a panic")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	c.Close()
+	jobs := c.Jobs()
+	if len(jobs) != 1 || jobs[0].Job != "report" || jobs[0].Runs < 3 {
+		t.Fatalf("got %+v", jobs)
+	}
+	if jobs[0].Panics != 1 || jobs[0].Failures != 1 || jobs[0].LastError != "" {
+		t.Fatalf("got %+v", jobs[0])
+	}
+}
+
+type typoJob struct{ reportJob }
+
+func (typoJob) Schedule() string { return "0 25 * * *" }
+
+type startRecorder struct {
+	started bool
+}
+
+func (s *startRecorder) Start(context.Context) error {
+	s.started = true
+	return nil
+}
+
+func TestContainer_ScheduledInvalid(t *testing.T) {
+	c := New()
+	starter := new(startRecorder)
+	c.Register(starter, Name("starter"))
+	c.Register(new(typoJob), Name("typo"))
+	if err := c.Build(); err == nil {
+		t.Fatal("expected an invalid schedule to fail Build")
+	}
+	if err := c.Start(context.Background()); err == nil {
+		t.Fatal("expected an invalid schedule to fail Start")
+	}
+	if starter.started {
+		t.Fatal("an invalid schedule should fail Start before the beans start")
+	}
+}
+
+func TestParseSchedule(t *testing.T) {
+	from := time.Date(2024, time.January, 31, 10, 7, 30, 0, time.UTC)
+	cases := []struct {
+		spec string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2024, time.January, 31, 10, 15, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, time.January, 31, 11, 0, 0, 0, time.UTC)},
+		{"30 2 * * 1-5", time.Date(2024, time.February, 1, 2, 30, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 9 1 * 7", time.Date(2024, time.February, 1, 9, 0, 0, 0, time.UTC)},
+		{"0 0 31 2 *", time.Time{}},
+		{"@every 90s", from.Add(90 * time.Second)},
+	}
+	for _, tc := range cases {
+		s, err := parseSchedule(tc.spec)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.spec, err)
+		}
+		if got := s.next(from); !got.Equal(tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.spec, got, tc.want)
+		}
+	}
+	for _, spec := range []string{"* * * *", "60 * * * *", "*/0 * * * *", "@every -1s", "a * * * *"} {
+		if _, err := parseSchedule(spec); err == nil {
+			t.Errorf("%s: expected an error", spec)
+		}
+	}
+}
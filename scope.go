@@ -0,0 +1,75 @@
+package keeper
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Tags may name the container a dependency comes from, when beans of the
+// same name live in several containers of a hierarchy:
+//
+//	db      *sql.DB `name:"parent:db"`                // the db of the parent, even if the container has one
+//	client  *Client `name:"child(analytics):client"` // the client of the child named analytics
+//
+// A scoped dependency is resolved from that container only, the others are
+// resolved from the container first, then from its parent.
+const (
+	_parentScope = "parent:"
+	_childScope  = "child("
+)
+
+// ChildOf is an Option that makes the container a child of parent under
+// name: it resolves the names it doesn't have from parent like WithParent,
+// and the beans of parent may refer to its beans with `name:"child(name):bean"`.
+// It panics if parent already has a child of that name.
+func ChildOf(parent Keeper, name string) Option {
+	return optionFunc(func(c *Container) {
+		WithParent(parent).applyOption(c)
+		p, ok := parent.(*Container)
+		if !ok {
+			return
+		}
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if _, dup := p.children[name]; dup {
+			panic(fmt.Sprintf("keeper: ChildOf called twice for %s", name))
+		}
+		if p.children == nil {
+			p.children = make(map[string]*Container)
+		}
+		p.children[name] = c
+	})
+}
+
+// resolveScoped resolves name when it's scoped to the parent or a child,
+// and reports whether it was.
+func (c *Container) resolveScoped(name string) (interface{}, bool) {
+	switch {
+	case strings.HasPrefix(name, _parentScope):
+		if c.parent == nil {
+			return nil, true
+		}
+		return c.parent(strings.TrimPrefix(name, _parentScope)), true
+	case strings.HasPrefix(name, _childScope):
+		end := strings.Index(name, "):")
+		if end < 0 {
+			return nil, false
+		}
+		c.mu.RLock()
+		child := c.children[name[len(_childScope):end]]
+		c.mu.RUnlock()
+		if child == nil {
+			return nil, true
+		}
+		return child.lookupLocal(name[end+2:]), true
+	}
+	return nil, false
+}
+
+// lookupLocal is lookup without asking the parent.
+func (c *Container) lookupLocal(name string) interface{} {
+	if _, ok := c.published()[name]; !ok {
+		return nil
+	}
+	return c.lookup(name)
+}
@@ -0,0 +1,40 @@
+package keeper
+
+import "testing"
+
+type scopedCtl struct {
+	local  *HelloSrv `name:"helloService"`
+	parent *HelloSrv `name:"parent:helloService"`
+}
+
+type analyticsCtl struct {
+	client *HelloSrv `name:"child(analytics):helloService"`
+}
+
+func TestContainer_ScopedTags(t *testing.T) {
+	unsafeOnly(t)
+	root := New()
+	rootSrv := &HelloSrv{word: "root"}
+	root.Register(rootSrv, Name("helloService"))
+	child := New(ChildOf(root, "analytics"))
+	childSrv := &HelloSrv{word: "analytics"}
+	child.Register(childSrv, Name("helloService"))
+
+	ctl := new(scopedCtl)
+	if err := child.Register(ctl, Name("scopedCtl")); err != nil {
+		t.Fatal(err)
+	}
+	if ctl.local != childSrv || ctl.parent != rootSrv {
+		t.Fatalf("got %+v", ctl)
+	}
+	actl := new(analyticsCtl)
+	if err := root.Register(actl, Name("analyticsCtl")); err != nil {
+		t.Fatal(err)
+	}
+	if actl.client != childSrv {
+		t.Fatalf("got %+v", actl)
+	}
+	if err := New().Register(new(analyticsCtl), Name("analyticsCtl")); err == nil {
+		t.Fatal("expected a missing child to fail")
+	}
+}
@@ -0,0 +1,74 @@
+package keeper
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// WithSeed is an Option that makes the behaviors of the container which
+// would vary from run to run reproducible, to hunt flaky tests down: the
+// Random balancers pick from a source seeded with seed, and Warm and the
+// StartTask funcs run one at a time in an order shuffled by seed rather than
+// concurrently. A seed reproduces an order, other seeds try others.
+func WithSeed(seed int64) Option {
+	return optionFunc(func(c *Container) {
+		c.rand = &seededRand{r: rand.New(rand.NewSource(seed))}
+	})
+}
+
+// seededRand is a rand.Rand safe for concurrent use.
+type seededRand struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+func (s *seededRand) intn(n int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.r.Intn(n)
+}
+
+func (s *seededRand) shuffle(n int, swap func(i, j int)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.r.Shuffle(n, swap)
+}
+
+// Random returns a Balancer picking the members at random, in proportion to
+// their weight. Its picks are reproducible when the container has WithSeed.
+func Random() Balancer {
+	return new(randomBalancer)
+}
+
+type randomBalancer struct {
+	rand *seededRand // set by WithSeed, the global source otherwise
+}
+
+func (r *randomBalancer) Pick(members []Member) int {
+	total := 0
+	for _, m := range members {
+		total += m.Weight
+	}
+	var n int
+	if r.rand != nil {
+		n = r.rand.intn(total)
+	} else {
+		n = rand.Intn(total)
+	}
+	for i, m := range members {
+		if n -= m.Weight; n < 0 {
+			return i
+		}
+	}
+	return len(members) - 1
+}
+
+// seedBalancers has the Random balancers of the container pick from its
+// seeded source.
+func (c *Container) seedBalancers() {
+	for _, b := range c.balancers {
+		if r, ok := b.(*randomBalancer); ok {
+			r.rand = c.rand
+		}
+	}
+}
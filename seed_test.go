@@ -0,0 +1,44 @@
+package keeper
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func seededPicks(seed int64) []string {
+	c := New(WithSeed(seed), WithBalancer("upstreams", Random()))
+	for _, name := range []string{"a", "b", "c"} {
+		c.Register(&HelloSrv{word: name}, Name(name), Group("upstreams"))
+	}
+	picks := make([]string, 20)
+	for i := range picks {
+		picks[i] = c.Find("upstreams").(*HelloSrv).word
+	}
+	return picks
+}
+
+func seededStarts(seed int64) []string {
+	var started []string
+	c := New(WithSeed(seed))
+	for _, name := range []string{"a", "b", "c", "d"} {
+		name := name
+		c.Register(&HelloSrv{}, Name(name), StartTask(func(context.Context) error {
+			started = append(started, name) // tasks run one at a time
+			return nil
+		}))
+	}
+	if err := c.Start(context.Background()); err != nil {
+		panic(err)
+	}
+	return started
+}
+
+func TestWithSeed(t *testing.T) {
+	if a, b := seededPicks(42), seededPicks(42); !reflect.DeepEqual(a, b) {
+		t.Errorf("Random picked %v, then %v with the same seed", a, b)
+	}
+	if a, b := seededStarts(7), seededStarts(7); !reflect.DeepEqual(a, b) || len(a) != 4 {
+		t.Errorf("start tasks ran in the order %v, then %v with the same seed", a, b)
+	}
+}
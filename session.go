@@ -0,0 +1,229 @@
+package keeper
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+)
+
+// SessionStore keeps the session scoped beans of Sessions between requests,
+// by session ID. A store of a remote cache like redis encodes the beans as
+// it sees fit, and returns decoded copies.
+type SessionStore interface {
+	// Load returns the beans of session, false if there are none.
+	Load(session string) (map[string]interface{}, bool)
+	// Save stores the beans of session, replacing those stored.
+	Save(session string, beans map[string]interface{}) error
+	// Delete forgets the beans of session.
+	Delete(session string) error
+}
+
+// Sessions builds session scoped beans, like the state of a multi-step
+// wizard, which last across the requests of a session instead of the
+// container or a single request. The beans of a session are built on the
+// first request of the session, by the factories given to Register, then
+// kept by the SessionStore.
+//
+// Context puts them in the context of a request as ctx values, for
+// ProvideContext to inject into fields tagged `ctx:"name"`, and Save
+// stores them back once the request is handled:
+//
+//	ctx, err := sessions.Context(r.Context(), sessionID)
+//	...
+//	err = c.ProvideContext(ctx, h) // h.wizard `ctx:"wizard"` is the bean of the session
+//	...
+//	err = sessions.Save(ctx)
+//
+// The requests of a session share its beans when the store doesn't copy
+// them, like the memory store, so they must be safe for concurrent use.
+type Sessions struct {
+	k     Keeper
+	store SessionStore
+
+	mu        sync.RWMutex
+	factories map[string]FactoryFunc
+	order     []string
+	building  map[string]*sessionLock // by session ID, while its beans are built
+}
+
+type sessionLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// NewSessions returns the Sessions of the beans of k, kept by store.
+func NewSessions(k Keeper, store SessionStore) *Sessions {
+	return &Sessions{k: k, store: store, factories: make(map[string]FactoryFunc), building: make(map[string]*sessionLock)}
+}
+
+// Register registers factory building the session scoped bean of name for
+// each session.
+func (s *Sessions) Register(name string, factory FactoryFunc) error {
+	if name == "" || factory == nil {
+		return fmt.Errorf("cannot register session bean %q without a name and a factory", name)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, dup := s.factories[name]; dup {
+		return fmt.Errorf("register duplicate! session bean %s already registered", name)
+	}
+	s.factories[name] = factory
+	s.order = append(s.order, name)
+	return nil
+}
+
+type sessionKey struct{}
+
+// sessionBeans are the beans of a session carried by the context of a request.
+type sessionBeans struct {
+	session string
+	beans   map[string]interface{}
+}
+
+// Context returns a copy of ctx carrying the beans of session, restored from
+// the store, or built if the session has none yet, and saved right away. The
+// first requests of a session get the same beans, they're built once.
+func (s *Sessions) Context(ctx context.Context, session string) (context.Context, error) {
+	beans, missing := s.load(session)
+	if len(missing) > 0 {
+		unlock := s.lockSession(session)
+		defer unlock()
+		beans, missing = s.load(session) // built meanwhile by a concurrent request
+		for _, name := range missing {
+			s.mu.RLock()
+			factory := s.factories[name]
+			s.mu.RUnlock()
+			bean, err := factory(s.k)
+			if err != nil {
+				return ctx, fmt.Errorf("failed to build session bean %s: %v", name, err)
+			}
+			beans[name] = bean
+		}
+		if len(missing) > 0 {
+			if err := s.store.Save(session, beans); err != nil {
+				return ctx, fmt.Errorf("failed to save session %s: %v", session, err)
+			}
+		}
+	}
+	for name, bean := range beans {
+		ctx = WithValue(ctx, name, bean)
+	}
+	return context.WithValue(ctx, sessionKey{}, &sessionBeans{session: session, beans: beans}), nil
+}
+
+// load returns a copy of the stored beans of session, and the names of the
+// beans it has yet to build.
+func (s *Sessions) load(session string) (map[string]interface{}, []string) {
+	stored, _ := s.store.Load(session)
+	beans := make(map[string]interface{}, len(stored))
+	for name, bean := range stored { // the stored map may be shared with other requests
+		beans[name] = bean
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var missing []string
+	for _, name := range s.order {
+		if _, ok := beans[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	return beans, missing
+}
+
+// lockSession serializes the builds of the beans of session, and returns
+// the func releasing it.
+func (s *Sessions) lockSession(session string) func() {
+	s.mu.Lock()
+	l, ok := s.building[session]
+	if !ok {
+		l = new(sessionLock)
+		s.building[session] = l
+	}
+	l.refs++
+	s.mu.Unlock()
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+		s.mu.Lock()
+		if l.refs--; l.refs == 0 {
+			delete(s.building, session)
+		}
+		s.mu.Unlock()
+	}
+}
+
+// Save stores the beans of the session of ctx, returned by Context, with
+// the changes of the request.
+func (s *Sessions) Save(ctx context.Context) error {
+	sb, ok := ctx.Value(sessionKey{}).(*sessionBeans)
+	if !ok {
+		return fmt.Errorf("no session in the context")
+	}
+	if err := s.store.Save(sb.session, sb.beans); err != nil {
+		return fmt.Errorf("failed to save session %s: %v", sb.session, err)
+	}
+	return nil
+}
+
+// End forgets the beans of session, the next request of the session gets
+// new ones.
+func (s *Sessions) End(session string) error {
+	return s.store.Delete(session)
+}
+
+// NewMemorySessionStore returns a SessionStore keeping the beans of the
+// capacity most recently used sessions in memory, as they are.
+func NewMemorySessionStore(capacity int) SessionStore {
+	return &memorySessionStore{capacity: capacity, sessions: make(map[string]*list.Element), lru: list.New()}
+}
+
+type memorySessionStore struct {
+	mu       sync.Mutex
+	capacity int
+	sessions map[string]*list.Element // of the lru
+	lru      *list.List               // of *storedSession, most recently used first
+}
+
+type storedSession struct {
+	session string
+	beans   map[string]interface{}
+}
+
+func (m *memorySessionStore) Load(session string) (map[string]interface{}, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.sessions[session]
+	if !ok {
+		return nil, false
+	}
+	m.lru.MoveToFront(e)
+	return e.Value.(*storedSession).beans, true
+}
+
+func (m *memorySessionStore) Save(session string, beans map[string]interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e, ok := m.sessions[session]; ok {
+		e.Value.(*storedSession).beans = beans
+		m.lru.MoveToFront(e)
+		return nil
+	}
+	m.sessions[session] = m.lru.PushFront(&storedSession{session: session, beans: beans})
+	for m.capacity > 0 && m.lru.Len() > m.capacity {
+		oldest := m.lru.Back()
+		m.lru.Remove(oldest)
+		delete(m.sessions, oldest.Value.(*storedSession).session)
+	}
+	return nil
+}
+
+func (m *memorySessionStore) Delete(session string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e, ok := m.sessions[session]; ok {
+		m.lru.Remove(e)
+		delete(m.sessions, session)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package keeper
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type wizardState struct {
+	step int
+}
+
+type wizardHandler struct {
+	srv    *HelloSrv    `name:"helloService"`
+	wizard *wizardState `ctx:"wizard"`
+}
+
+func TestSessions(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	c.Register(&HelloSrv{word: "hello"}, Name("helloService"))
+	sessions := NewSessions(c, NewMemorySessionStore(1))
+	built := 0
+	sessions.Register("wizard", func(Keeper) (interface{}, error) {
+		built++
+		return new(wizardState), nil
+	})
+	request := func(session string) *wizardHandler {
+		ctx, err := sessions.Context(context.Background(), session)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h := new(wizardHandler)
+		if err := c.ProvideContext(ctx, h); err != nil {
+			t.Fatal(err)
+		}
+		h.wizard.step++
+		if err := sessions.Save(ctx); err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+	request("alice")
+	if h := request("alice"); h.wizard.step != 2 || built != 1 {
+		t.Fatalf("session should keep its bean, got step %d after %d builds", h.wizard.step, built)
+	}
+	request("bob") // evicts alice
+	if h := request("alice"); h.wizard.step != 1 || built != 3 {
+		t.Fatalf("evicted session should start over, got step %d after %d builds", h.wizard.step, built)
+	}
+	sessions.End("alice")
+	if h := request("alice"); h.wizard.step != 1 || built != 4 {
+		t.Fatalf("ended session should start over, got step %d after %d builds", h.wizard.step, built)
+	}
+}
+
+func TestSessionsRegisterDuplicate(t *testing.T) {
+	sessions := NewSessions(New(), NewMemorySessionStore(0))
+	factory := func(Keeper) (interface{}, error) { return new(wizardState), nil }
+	if err := sessions.Register("wizard", factory); err != nil {
+		t.Fatal(err)
+	}
+	if err := sessions.Register("wizard", factory); err == nil {
+		t.Fatal("duplicate session bean should fail")
+	}
+	if err := sessions.Save(context.Background()); err == nil {
+		t.Fatal("Save without a session should fail")
+	}
+}
+
+func TestSessionsConcurrentFirstRequests(t *testing.T) {
+	sessions := NewSessions(New(), NewMemorySessionStore(0))
+	var built int32
+	sessions.Register("wizard", func(Keeper) (interface{}, error) {
+		atomic.AddInt32(&built, 1)
+		time.Sleep(time.Millisecond)
+		return new(wizardState), nil
+	})
+	var wg sync.WaitGroup
+	wizards := make([]interface{}, 8)
+	for i := range wizards {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx, err := sessions.Context(context.Background(), "alice")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			wizards[i] = ctx.Value(ContextKey("wizard"))
+		}(i)
+	}
+	wg.Wait()
+	if n := atomic.LoadInt32(&built); n != 1 {
+		t.Fatalf("got %d builds, want 1", n)
+	}
+	for _, w := range wizards {
+		if w != wizards[0] {
+			t.Fatal("the first requests of a session should share its beans")
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package keeper
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestContainer_DuplicateSite(t *testing.T) {
+	c := New()
+	c.Register(new(HelloSrv), Name("helloService"))
+	err := c.Register(new(HelloSrv), Name("helloService"))
+	if err == nil || !strings.Contains(err.Error(), "site_test.go:10 (registered at ") || !strings.HasSuffix(err.Error(), "site_test.go:11)") {
+		t.Fatalf("expected the sites of both registrations, got %v", err)
+	}
+}
+
+func TestContainer_Site(t *testing.T) {
+	c := New()
+	c.Register(new(HelloSrv), Name("helloService"))
+	if site := c.Beans()[0].Site; !strings.HasSuffix(site, "site_test.go:19") {
+		t.Fatalf("got site %q", site)
+	}
+	c = New(WithoutCallSites())
+	c.Register(new(HelloSrv), Name("helloService"))
+	if site := c.Beans()[0].Site; site != "" {
+		t.Fatalf("got site %q without call sites", site)
+	}
+}
@@ -0,0 +1,143 @@
+package keeper
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SpecVersion is the version of the Spec format written by ExportSpec.
+// It's incremented on incompatible changes only, new optional properties
+// may be added to a version.
+const SpecVersion = 1
+
+// Spec is the portable description of a container graph, for the tooling
+// which checks or catalogs the wiring of services, whatever its language.
+// SpecSchema is its JSON Schema.
+type Spec struct {
+	Version int        `json:"version"`
+	Beans   []SpecBean `json:"beans"`
+	Edges   []SpecEdge `json:"edges"`
+}
+
+// SpecBean is a bean of a Spec.
+type SpecBean struct {
+	Name        string            `json:"name"`
+	Type        string            `json:"type,omitempty"` // empty for factory beans not built yet
+	Description string            `json:"description,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Lazy        bool              `json:"lazy,omitempty"`
+	Adopted     bool              `json:"adopted,omitempty"`
+}
+
+// SpecEdge is a dependency of a Spec, From having To injected.
+type SpecEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// External is set when To isn't a bean of the spec, like beans of a parent container.
+	External bool `json:"external,omitempty"`
+}
+
+// SpecSchema is the JSON Schema of the version SpecVersion of Spec.
+const SpecSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "$id": "https://github.com/tooky0630/keeper/spec/v1.json",
+  "title": "keeper container graph",
+  "type": "object",
+  "required": ["version", "beans", "edges"],
+  "properties": {
+    "version": {"const": 1},
+    "beans": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "required": ["name"],
+        "properties": {
+          "name": {"type": "string", "minLength": 1},
+          "type": {"type": "string"},
+          "description": {"type": "string"},
+          "labels": {"type": "object", "additionalProperties": {"type": "string"}},
+          "lazy": {"type": "boolean"},
+          "adopted": {"type": "boolean"}
+        }
+      }
+    },
+    "edges": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "required": ["from", "to"],
+        "properties": {
+          "from": {"type": "string", "minLength": 1},
+          "to": {"type": "string", "minLength": 1},
+          "external": {"type": "boolean"}
+        }
+      }
+    }
+  }
+}
+`
+
+// ExportSpec describes the beans of the container and their dependencies
+// as a Spec, in registration order.
+func (c *Container) ExportSpec() Spec {
+	infos := c.Beans()
+	spec := Spec{
+		Version: SpecVersion,
+		Beans:   make([]SpecBean, 0, len(infos)),
+		Edges:   []SpecEdge{},
+	}
+	known := make(map[string]bool, len(infos))
+	for _, info := range infos {
+		known[info.Name] = true
+	}
+	for _, info := range infos {
+		spec.Beans = append(spec.Beans, SpecBean{
+			Name:        info.Name,
+			Type:        info.Type,
+			Description: info.Description,
+			Labels:      info.Labels,
+			Lazy:        info.Lazy,
+			Adopted:     info.Adopted,
+		})
+		for _, dep := range info.Dependencies {
+			spec.Edges = append(spec.Edges, SpecEdge{From: info.Name, To: dep, External: !known[dep]})
+		}
+	}
+	return spec
+}
+
+// ValidateSpec decodes a Spec from data, and checks it's of a supported
+// version and consistent: bean names are unique, and the edges join beans of
+// the spec, unless marked external.
+func ValidateSpec(data []byte) (*Spec, error) {
+	var spec Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("invalid spec: %v", err)
+	}
+	if spec.Version != SpecVersion {
+		return nil, fmt.Errorf("invalid spec: unsupported version %d, want %d", spec.Version, SpecVersion)
+	}
+	var errs multiError
+	known := make(map[string]bool, len(spec.Beans))
+	for i, bean := range spec.Beans {
+		switch {
+		case bean.Name == "":
+			errs = append(errs, fmt.Errorf("bean %d has no name", i))
+		case known[bean.Name]:
+			errs = append(errs, fmt.Errorf("bean %s is declared twice", bean.Name))
+		}
+		known[bean.Name] = true
+	}
+	for _, edge := range spec.Edges {
+		if !known[edge.From] {
+			errs = append(errs, fmt.Errorf("edge %s -> %s starts from an unknown bean", edge.From, edge.To))
+		}
+		if !known[edge.To] && !edge.External {
+			errs = append(errs, fmt.Errorf("edge %s -> %s points to an unknown bean", edge.From, edge.To))
+		}
+	}
+	if err := errs.errOrNil(); err != nil {
+		return nil, fmt.Errorf("invalid spec: %v", err)
+	}
+	return &spec, nil
+}
@@ -0,0 +1,50 @@
+package keeper
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestContainer_ExportSpec(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	c.Register(&HelloSrv{}, Name("helloService"), Label("tier", "core"))
+	c.Register(new(HelloCtl), Name("helloCtl"))
+	data, err := json.Marshal(c.ExportSpec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec, err := ValidateSpec(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spec.Beans) != 2 || spec.Beans[0].Labels["tier"] != "core" || spec.Beans[1].Type != "*keeper.HelloCtl" {
+		t.Fatalf("got %+v", spec.Beans)
+	}
+	if len(spec.Edges) != 1 || spec.Edges[0] != (SpecEdge{From: "helloCtl", To: "helloService"}) {
+		t.Fatalf("got %+v", spec.Edges)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(SpecSchema), &schema); err != nil {
+		t.Fatalf("SpecSchema isn't valid JSON: %v", err)
+	}
+}
+
+func TestValidateSpec(t *testing.T) {
+	cases := map[string]string{
+		`{"version": 2, "beans": [], "edges": []}`:                                      "unsupported version 2",
+		`{"version": 1, "beans": [{"name": "a"}, {"name": "a"}], "edges": []}`:          "bean a is declared twice",
+		`{"version": 1, "beans": [{"name": "a"}], "edges": [{"from": "a", "to": "b"}]}`: "points to an unknown bean",
+		`{"version": 1, "beans": [`:                                                     "invalid spec",
+	}
+	for data, want := range cases {
+		if _, err := ValidateSpec([]byte(data)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: got %v, want %q", data, err, want)
+		}
+	}
+	external := `{"version": 1, "beans": [{"name": "a"}], "edges": [{"from": "a", "to": "b", "external": true}]}`
+	if _, err := ValidateSpec([]byte(external)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,116 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// StartTask is a RegisterOption that has Start run fn concurrently with the
+// start tasks of the other beans, once the Starter beans have started and
+// before PhaseStart, for the slow starts which don't depend on each other,
+// like connecting consumers or loading caches. The first task to fail
+// cancels the context of the others, and fails Start with an error naming
+// its bean:
+//
+//	bean "kafkaConsumer" start failed: dial tcp: connection refused
+//
+// A bean may register several tasks. A task which panics fails like one
+// returning an error.
+func StartTask(fn func(ctx context.Context) error) RegisterOption {
+	return registerOptionFunc(func(options *registerOptions) {
+		options.StartTasks = append(options.StartTasks, fn)
+	})
+}
+
+// StartError is the failure of a start task.
+type StartError struct {
+	Bean string
+	Err  error
+}
+
+func (e *StartError) Error() string {
+	return fmt.Sprintf("bean %q start failed: %v", e.Bean, e.Err)
+}
+
+func (e *StartError) Unwrap() error { return e.Err }
+
+// runStartTasks runs the StartTask funcs of the beans, and returns the first
+// failure.
+func (c *Container) runStartTasks(ctx context.Context) error {
+	g, ctx := newTaskGroup(ctx)
+	var runs []func() error
+	for _, name := range c.dependencyOrder() {
+		c.mu.RLock()
+		tasks := c.opts[name].StartTasks
+		c.mu.RUnlock()
+		for _, fn := range tasks {
+			name, fn := name, fn
+			runs = append(runs, func() (err error) {
+				defer func() {
+					if p := recover(); p != nil {
+						err = panicError{value: p}
+					}
+					if err != nil {
+						c.recordError(name, err)
+						err = &StartError{Bean: name, Err: err}
+					}
+				}()
+				return fn(ctx)
+			})
+		}
+	}
+	if c.rand != nil { // one at a time, in the order of the seed
+		g.sequential = true
+		c.rand.shuffle(len(runs), func(i, j int) { runs[i], runs[j] = runs[j], runs[i] })
+	}
+	for _, run := range runs {
+		g.Go(run)
+	}
+	return g.Wait()
+}
+
+// taskGroup runs funcs concurrently, and cancels the context of the others
+// on the first failure, like golang.org/x/sync/errgroup. A sequential group
+// runs them by Go one at a time, and skips them after the first failure.
+type taskGroup struct {
+	wg         sync.WaitGroup
+	cancel     context.CancelFunc
+	once       sync.Once
+	err        error
+	sequential bool
+}
+
+func newTaskGroup(ctx context.Context) (*taskGroup, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &taskGroup{cancel: cancel}, ctx
+}
+
+func (g *taskGroup) Go(fn func() error) {
+	if g.sequential {
+		if g.err == nil {
+			if err := fn(); err != nil {
+				g.err = err
+				g.cancel()
+			}
+		}
+		return
+	}
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := fn(); err != nil {
+			g.once.Do(func() {
+				g.err = err
+				g.cancel()
+			})
+		}
+	}()
+}
+
+// Wait waits for the funcs, and returns the first failure.
+func (g *taskGroup) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+	return g.err
+}
@@ -0,0 +1,66 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStartTask(t *testing.T) {
+	c := New()
+	var running int32
+	both := make(chan struct{})
+	task := func(ctx context.Context) error {
+		if atomic.AddInt32(&running, 1) == 2 {
+			close(both)
+		}
+		select {
+		case <-both: // the tasks run concurrently
+			return nil
+		case <-time.After(time.Second):
+			return errors.New("ran alone")
+		}
+	}
+	c.Register(new(HelloSrv), Name("a"), StartTask(task))
+	c.Register(new(HelloSrv), Name("b"), StartTask(task))
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStartTaskFailure(t *testing.T) {
+	c := New()
+	refused := errors.New("connection refused")
+	canceled := make(chan error, 1)
+	c.Register(new(HelloSrv), Name("cache"), StartTask(func(ctx context.Context) error {
+		<-ctx.Done()
+		canceled <- ctx.Err()
+		return ctx.Err()
+	}))
+	c.Register(new(HelloSrv), Name("kafkaConsumer"), StartTask(func(context.Context) error {
+		return refused
+	}))
+	err := c.Start(context.Background())
+	var startErr *StartError
+	if !errors.As(err, &startErr) || startErr.Bean != "kafkaConsumer" || !errors.Is(err, refused) {
+		t.Fatalf("got %v", err)
+	}
+	if err.Error() != `bean "kafkaConsumer" start failed: connection refused` {
+		t.Errorf("got %q", err)
+	}
+	if err := <-canceled; err != context.Canceled {
+		t.Errorf("the other task got %v, want it canceled", err)
+	}
+}
+
+func TestStartTaskPanic(t *testing.T) {
+	c := New()
+	c.Register(new(HelloSrv), Name("loader"), StartTask(func(context.Context) error {
+		panic("boom")
+	}))
+	if err := c.Start(context.Background()); err == nil || err.Error() != `bean "loader" start failed: panic: boom` {
+		t.Fatalf("got %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package keeper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sync/atomic"
+)
+
+// BeanStatus is the progress of a bean through the container.
+type BeanStatus string
+
+const (
+	StatusReady       BeanStatus = "ready"
+	StatusPendingInit BeanStatus = "pending-init" // registered with InitOnStart, not started yet
+	StatusDeferred    BeanStatus = "deferred"     // waits for dependencies deferred by MissingDefer
+	StatusUnbuilt     BeanStatus = "unbuilt"      // factory bean not built yet
+	StatusFailed      BeanStatus = "failed"       // its registration failed
+)
+
+// BeanState is the state of a bean dumped by DumpState.
+type BeanState struct {
+	Name   string     `json:"name"`
+	Type   string     `json:"type,omitempty"`
+	Status BeanStatus `json:"status"`
+	Site   string     `json:"site,omitempty"`  // file:line of the registration
+	Err    string     `json:"error,omitempty"` // the last error of the bean
+}
+
+// StateEncoder writes the state of the beans for DumpState.
+type StateEncoder interface {
+	EncodeState(w io.Writer, beans []BeanState) error
+}
+
+// StateEncoderFunc is a func satisfying StateEncoder.
+type StateEncoderFunc func(w io.Writer, beans []BeanState) error
+
+func (f StateEncoderFunc) EncodeState(w io.Writer, beans []BeanState) error { return f(w, beans) }
+
+// TextState is the default StateEncoder, writing a line per bean:
+//
+//	name type status [site] ["error"]
+var TextState StateEncoder = StateEncoderFunc(func(w io.Writer, beans []BeanState) error {
+	for _, b := range beans {
+		typ := b.Type
+		if typ == "" {
+			typ = "-"
+		}
+		line := fmt.Sprintf("%s %s %s", b.Name, typ, b.Status)
+		if b.Site != "" {
+			line += " " + b.Site
+		}
+		if b.Err != "" {
+			line += fmt.Sprintf(" %q", b.Err)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+})
+
+// JSONState is a StateEncoder writing the beans as a JSON array.
+var JSONState StateEncoder = StateEncoderFunc(func(w io.Writer, beans []BeanState) error {
+	return json.NewEncoder(w).Encode(beans)
+})
+
+// WithStateEncoder is an Option that sets the format of DumpState, TextState by default.
+func WithStateEncoder(enc StateEncoder) Option {
+	return optionFunc(func(c *Container) {
+		c.stateEncoder = enc
+	})
+}
+
+// DumpOnPanic is an Option that dumps the state of the container into w when
+// an initializer or a Starter panics, before the panic goes on. Only the
+// first panic is dumped.
+func DumpOnPanic(w io.Writer) Option {
+	return optionFunc(func(c *Container) {
+		c.panicDump = w
+	})
+}
+
+// DumpState writes the beans in the Ordering of the container, followed by the beans
+// whose registration failed, with their status and last error.
+func (c *Container) DumpState(w io.Writer) error {
+	c.mu.RLock()
+	nodes := c.published()
+	initPending := make(map[string]bool, len(c.initPending))
+	for _, name := range c.initPending {
+		initPending[name] = true
+	}
+	deferred := make(map[string]bool, len(c.deferred))
+	for _, d := range c.deferred {
+		deferred[d.bean] = true
+	}
+	beans := make([]BeanState, 0, len(c.order))
+	registered := make(map[string]bool, len(c.order))
+	for _, name := range c.enumeration() {
+		registered[name] = true
+		state := BeanState{Name: name, Status: StatusReady, Site: c.opts[name].site, Err: c.lastErrors[name]}
+		bean := nodes[name]
+		if lazy, ok := bean.(*lazyBean); ok {
+			if bean = lazy.peek(); bean == nil {
+				state.Status = StatusUnbuilt
+			}
+		}
+		if bean != nil {
+			state.Type = reflect.TypeOf(bean).String()
+		}
+		switch {
+		case initPending[name]:
+			state.Status = StatusPendingInit
+		case deferred[name]:
+			state.Status = StatusDeferred
+		}
+		beans = append(beans, state)
+	}
+	for _, name := range c.failed {
+		if !registered[name] {
+			beans = append(beans, BeanState{Name: name, Status: StatusFailed, Err: c.lastErrors[name]})
+		}
+	}
+	enc := c.stateEncoder
+	c.mu.RUnlock()
+	if enc == nil {
+		enc = TextState
+	}
+	return enc.EncodeState(w, beans)
+}
+
+// recordError keeps err as the last error of the bean of name.
+func (c *Container) recordError(name string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.lastErrors == nil {
+		c.lastErrors = make(map[string]string)
+	}
+	if _, ok := c.lastErrors[name]; !ok {
+		c.failed = append(c.failed, name)
+	}
+	c.lastErrors[name] = err.Error()
+}
+
+// dumpOnPanic is deferred by the operations invoking beans, it dumps the
+// state on the first panic when DumpOnPanic is set.
+func (c *Container) dumpOnPanic(name string) {
+	if c.panicDump == nil {
+		return
+	}
+	if p := recover(); p != nil {
+		if atomic.CompareAndSwapInt32(&c.dumped, 0, 1) {
+			c.recordError(name, fmt.Errorf("panic: %v", p))
+			c.DumpState(c.panicDump)
+		}
+		panic(p)
+	}
+}
@@ -0,0 +1,69 @@
+package keeper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type panickingStarter struct{}
+
+func (panickingStarter) Start(context.Context) error { panic("boom") }
+
+func TestContainer_DumpState(t *testing.T) {
+	c := New(OnMissing(MissingDefer), WithoutCallSites())
+	c.Register(&HelloSrv{}, Name("helloService"))
+	c.Register(&lateInitializer{c: c}, Name("late"), InitOnStart())
+	c.Register(new(cyclicA), Name("a"))
+	c.RegisterFactory(func(Keeper) (interface{}, error) { return nil, errors.New("no network") }, Name("remote"))
+	c.Find("remote")
+	c.Register(nil, Name("broken"))
+
+	var b bytes.Buffer
+	if err := c.DumpState(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `helloService *keeper.HelloSrv ready
+late *keeper.lateInitializer pending-init
+a *keeper.cyclicA deferred
+remote - unbuilt "no network"
+broken - failed "can't provide an untyped nil"
+`
+	if b.String() != want {
+		t.Fatalf("got\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestContainer_DumpStateJSON(t *testing.T) {
+	c := New(WithStateEncoder(JSONState))
+	c.Register(&HelloSrv{}, Name("helloService"))
+	var b bytes.Buffer
+	c.DumpState(&b)
+	var beans []BeanState
+	if err := json.Unmarshal(b.Bytes(), &beans); err != nil {
+		t.Fatal(err)
+	}
+	if len(beans) != 1 || beans[0].Status != StatusReady {
+		t.Fatalf("got %+v", beans)
+	}
+}
+
+func TestContainer_DumpOnPanic(t *testing.T) {
+	var b bytes.Buffer
+	c := New(DumpOnPanic(&b))
+	c.Register(panickingStarter{}, Name("starter"))
+	func() {
+		defer func() {
+			if p := recover(); p != "boom" {
+				t.Fatalf("got panic %v, want it to go on", p)
+			}
+		}()
+		c.Start(context.Background())
+	}()
+	if !strings.Contains(b.String(), `state_test.go:57 "panic: boom"`) {
+		t.Fatalf("got dump %q", b.String())
+	}
+}
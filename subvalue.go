@@ -0,0 +1,94 @@
+package keeper
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// A name tagged field could be injected a value within a map or struct bean,
+// rather than the bean itself, with the path of the value in brackets:
+//
+//	type Client struct {
+//		readTimeout time.Duration `name:"settings[timeouts.read]"`
+//	}
+//
+// The path is navigated like the sections of WithConfigStruct. The value is
+// converted to the type of the field when it's of another numeric type, and
+// parsed like a value tagged field when it's a string, so "5s" gives 5
+// seconds. A registered bean whose name has brackets, like a generic
+// "repo[model.User]", takes precedence. The field depends on the bean, but
+// Swap doesn't update it, as it holds a copy of the value.
+
+// splitPath splits name into the bean and the path of name[path].
+func splitPath(name string) (bean, path string, ok bool) {
+	i := strings.IndexByte(name, '[')
+	if i <= 0 || !strings.HasSuffix(name, "]") {
+		return "", "", false
+	}
+	return name[:i], name[i+1 : len(name)-1], true
+}
+
+// resolvePath resolves name like resolve, or the bean of name[path] when
+// name isn't registered, then path is the path of the value within it.
+func (c *Container) resolvePath(name string) (elem interface{}, bean, path string) {
+	if elem = c.resolve(name); elem != nil {
+		return elem, name, ""
+	}
+	bean, path, ok := splitPath(name)
+	if !ok {
+		return nil, name, ""
+	}
+	if elem = c.resolve(bean); elem == nil {
+		return nil, name, ""
+	}
+	return elem, bean, path
+}
+
+// subValue returns the value at path within bean, converted to typ, and
+// reports whether there's a value at path.
+func subValue(typ reflect.Type, bean interface{}, path string) (reflect.Value, bool, error) {
+	v, ok, err := configSection(reflect.ValueOf(bean), path)
+	if err != nil || !ok {
+		return v, ok, err
+	}
+	for v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	if nv, err := assignValue(typ, v.Interface(), true); err == nil {
+		return nv, true, nil
+	}
+	if v.Kind() == reflect.String && typ.Kind() != reflect.String {
+		nv := reflect.New(typ).Elem()
+		if err := parseValue(nv, v.String()); err != nil {
+			return v, true, err
+		}
+		return nv, true, nil
+	}
+	return v, true, fmt.Errorf("cannot use %v as %v", v.Type(), typ)
+}
+
+// loadSubValue sets the i-th field of the struct ptr points to, tagged with
+// bean[path], to the value at path within elem, the bean.
+func (c *Container) loadSubValue(ptr interface{}, i int, elem interface{}, bean, path string, spec tagSpec, options registerOptions, w *wiring) (bool, error) {
+	fv := reflect.ValueOf(ptr).Elem().Field(i)
+	nv, ok, err := subValue(fv.Type(), elem, path)
+	if err != nil {
+		return false, fmt.Errorf("failed to load %s[%s] into %s: %v", bean, path, fieldPath(ptr, i), err)
+	}
+	if !ok {
+		if spec.flag(_optionalTag) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to load %s[%s], %s has no %s", bean, path, bean, path)
+	}
+	if fv, err = settable(fv); err != nil {
+		return false, fmt.Errorf("failed to load %s[%s] into %s: %v", bean, path, fieldPath(ptr, i), err)
+	}
+	fv.Set(nv)
+	if options.Name != "" { // Provider has nothing to record
+		w.deps = append(w.deps, bean)
+	}
+	c.notifyInject(Injection{Bean: options.Name, Field: reflect.TypeOf(ptr).Elem().Field(i).Name, Source: bean})
+	return true, nil
+}
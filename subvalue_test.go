@@ -0,0 +1,47 @@
+package keeper
+
+import (
+	"testing"
+	"time"
+)
+
+type timeouts struct {
+	Read  string `json:"read"`
+	Write int
+}
+
+type settingsClient struct {
+	readTimeout time.Duration `name:"settings[timeouts.read]"`
+	retries     int64         `name:"settings[retries]"`
+	write       int           `name:"limits[Timeouts.write]"`
+	missing     string        `name:"settings[missing],optional"`
+}
+
+func TestContainer_SubValue(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	c.Register(&map[string]interface{}{
+		"timeouts": map[string]interface{}{"read": "5s"},
+		"retries":  3, // an int, injected into an int64
+	}, Name("settings"))
+	c.Register(&struct{ Timeouts timeouts }{Timeouts: timeouts{Write: 7}}, Name("limits"))
+	client := new(settingsClient)
+	if err := c.Register(client, Name("client")); err != nil {
+		t.Fatal(err)
+	}
+	if client.readTimeout != 5*time.Second || client.retries != 3 || client.write != 7 || client.missing != "" {
+		t.Fatalf("got %+v", client)
+	}
+	for _, b := range c.Beans() {
+		if b.Name == "client" && len(b.Dependencies) != 3 {
+			t.Fatalf("got dependencies %v", b.Dependencies)
+		}
+	}
+
+	err := c.Register(&struct {
+		port int `name:"settings[port]"`
+	}{}, Name("server"))
+	if err == nil {
+		t.Fatal("expected error for a missing sub-value")
+	}
+}
@@ -0,0 +1,146 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrReadOnly is returned by attempts to swap a ReadOnly bean, and to modify
+// the container through a View.
+var ErrReadOnly = errors.New("bean is read-only")
+
+// ReadOnly is a RegisterOption for beans owned elsewhere, like process-wide
+// clients: the container never injects into them, swaps or destroys them.
+func ReadOnly() RegisterOption {
+	return registerOptionFunc(func(options *registerOptions) {
+		options.ReadOnly = true
+	})
+}
+
+func (c *Container) isReadOnly(name string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.opts[name].ReadOnly
+}
+
+// Swap replaces the bean of name with bean, which is loaded with the options
+// of the replaced one, and updates the fields of the dependents which got the
+// old bean injected. When bean can't be set into one of these fields, Swap
+// fails and leaves the old bean in place. It returns the old bean, which
+// isn't destroyed. Dependents may be in use meanwhile, so they should guard
+// the swapped fields.
+func (c *Container) Swap(name string, bean interface{}) (interface{}, error) {
+	c.mu.RLock()
+	options, ok := c.opts[name]
+	c.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("cannot swap %s, it isn't registered", name)
+	}
+	if options.ReadOnly {
+		return nil, fmt.Errorf("cannot swap %s: %w", name, ErrReadOnly)
+	}
+	if bean == nil {
+		return nil, fmt.Errorf("cannot swap %s with nil", name)
+	}
+	if err := options.checkImplements(bean); err != nil {
+		return nil, fmt.Errorf("cannot swap %s: %v", name, err)
+	}
+	if err := c.checkSwap(name, bean); err != nil {
+		return nil, err
+	}
+	var w wiring
+	if options.loadable(bean) {
+		var err error
+		if w, err = c.load(context.Background(), bean, options); err != nil {
+			return nil, err
+		}
+	}
+
+	c.mu.Lock()
+	nodes := c.published()
+	old := nodes[name]
+	next := make(map[string]interface{}, len(nodes))
+	for n, b := range nodes {
+		next[n] = b
+	}
+	next[name] = bean
+	var fields []injectedField
+	for _, f := range c.injected {
+		if f.source == name {
+			fields = append(fields, f)
+		}
+	}
+	c.nodes.Store(next)
+	c.deps[name] = w.deps
+	values := c.values[:0:0] // the wiring of the old bean goes with it
+	for _, v := range c.values {
+		if v.bean != name {
+			values = append(values, v)
+		}
+	}
+	c.values = append(values, w.values...)
+	injected := c.injected[:0:0]
+	for _, f := range c.injected {
+		if f.bean != name {
+			injected = append(injected, f)
+		}
+	}
+	c.injected = append(injected, w.fields...)
+	c.mu.Unlock()
+	if c.vars != nil {
+		c.vars.swaps.Add(1)
+	}
+
+	for _, f := range fields {
+		if err := c.reinject(f, bean); err != nil {
+			return old, fmt.Errorf("swapped %s, but %v", name, err)
+		}
+	}
+	if lazy, ok := old.(*lazyBean); ok {
+		old = lazy.peek()
+	}
+	return old, nil
+}
+
+// checkSwap checks that bean could be set into every field which got the
+// bean of name injected, and that the dependents hold the capabilities it
+// requires, so that Swap publishes it only when the dependents follow.
+func (c *Container) checkSwap(name string, bean interface{}) error {
+	c.mu.RLock()
+	var fields []injectedField
+	var dependents []registerOptions
+	for _, f := range c.injected {
+		if f.source == name {
+			fields = append(fields, f)
+			dependents = append(dependents, c.opts[f.bean]) // Provider beans hold no capability
+		}
+	}
+	c.mu.RUnlock()
+	for i, f := range fields {
+		typ := reflect.TypeOf(f.ptr).Elem()
+		if err := c.checkCapability(name, bean, dependents[i]); err != nil {
+			return fmt.Errorf("cannot swap %s, it can't be loaded into %s.%s: %w", name, typeName(typ), typ.Field(f.field).Name, err)
+		}
+		if _, err := c.assign(typ.Field(f.field).Type, bean); err != nil {
+			return fmt.Errorf("cannot swap %s, it can't be loaded into %s.%s: %v", name, typeName(typ), typ.Field(f.field).Name, err)
+		}
+	}
+	return nil
+}
+
+// reinject sets bean into the field f.
+func (c *Container) reinject(f injectedField, bean interface{}) error {
+	typ := reflect.TypeOf(f.ptr).Elem()
+	fv := reflect.ValueOf(f.ptr).Elem().Field(f.field)
+	nv, err := c.assign(fv.Type(), bean)
+	if err != nil {
+		return fmt.Errorf("failed to load %s into %s.%s: %v", f.source, typeName(typ), typ.Field(f.field).Name, err)
+	}
+	if fv, err = settable(fv); err != nil {
+		return err
+	}
+	fv.Set(nv)
+	return nil
+}
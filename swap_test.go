@@ -0,0 +1,94 @@
+package keeper
+
+import (
+	"errors"
+	"testing"
+)
+
+type closableClient struct {
+	closed bool
+}
+
+func (cl *closableClient) Destroy() error {
+	cl.closed = true
+	return nil
+}
+
+func TestContainer_Swap(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	c.Register(&HelloSrv{word: "primary"}, Name("helloService"))
+	ctl := new(HelloCtl)
+	c.Register(ctl, Name("helloCtl"))
+	old, err := c.Swap("helloService", &HelloSrv{word: "fallback"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if old.(*HelloSrv).word != "primary" {
+		t.Fatalf("got old %+v", old)
+	}
+	if ctl.helloSrv.word != "fallback" || c.Find("helloService").(*HelloSrv).word != "fallback" {
+		t.Fatal("dependents should see the swapped bean")
+	}
+	if _, err := c.Swap("missing", new(HelloSrv)); err == nil {
+		t.Fatal("expected error for unregistered bean")
+	}
+	if _, err := c.Swap("helloService", new(HelloCtl)); err == nil {
+		t.Fatal("expected error for a bean of another type")
+	}
+	if _, ok := c.Find("helloService").(*HelloSrv); !ok || ctl.helloSrv.word != "fallback" {
+		t.Fatal("a rejected swap should keep the old bean")
+	}
+}
+
+func TestContainer_ReadOnly(t *testing.T) {
+	c := New()
+	shared := new(closableClient)
+	owned := new(closableClient)
+	if err := c.Register(shared, Name("shared"), ReadOnly()); err != nil {
+		t.Fatal(err)
+	}
+	c.Register(owned, Name("owned"))
+	if _, err := c.Swap("shared", new(closableClient)); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("got %v, want ErrReadOnly", err)
+	}
+	// a read-only bean is never injected into
+	if err := c.Register(new(HelloCtl), Name("helloCtl"), ReadOnly()); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if shared.closed || !owned.closed {
+		t.Fatalf("got shared closed %v, owned closed %v", shared.closed, owned.closed)
+	}
+}
+
+func TestContainer_SwapDropsWiring(t *testing.T) {
+	unsafeOnly(t)
+	src := &mapSource{values: map[string]string{"http.addr": ":8080"}}
+	c := New(WithConfig(src))
+	c.Register(&HelloSrv{word: "primary"}, Name("helloService"))
+	oldCtl, oldSrv := new(HelloCtl), new(guardedServer)
+	c.Register(oldCtl, Name("helloCtl"))
+	c.Register(oldSrv, Name("guardedServer"))
+	newCtl, newSrv := new(HelloCtl), new(guardedServer)
+	if _, err := c.Swap("helloCtl", newCtl); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Swap("guardedServer", newSrv); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Swap("helloService", &HelloSrv{word: "fallback"}); err != nil {
+		t.Fatal(err)
+	}
+	src.values["http.addr"] = ":9090"
+	src.onChange([]string{"http.addr"})
+	if oldCtl.helloSrv.word != "primary" || oldSrv.Addr() != ":8080" {
+		t.Fatal("the swapped out beans should not be wired anymore")
+	}
+	if newCtl.helloSrv.word != "fallback" || newSrv.Addr() != ":9090" {
+		t.Fatalf("got %+v and %q", newCtl.helloSrv, newSrv.Addr())
+	}
+}
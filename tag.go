@@ -0,0 +1,127 @@
+package keeper
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// tagSpec is a parsed `name` or `value` tag: the bean name or config key,
+// followed by flags like optional and key=value options like default='x'.
+type tagSpec struct {
+	name    string
+	flags   map[string]bool
+	options map[string]string
+}
+
+// flag reports whether the flag is set.
+func (s tagSpec) flag(name string) bool {
+	return s.flags[name]
+}
+
+// option returns the value of the key=value option.
+func (s tagSpec) option(key string) (string, bool) {
+	v, ok := s.options[key]
+	return v, ok
+}
+
+var (
+	tagsMu sync.Mutex   // serializes writers of tags
+	tags   atomic.Value // map[string]tagSpec, immutable once published
+)
+
+// parseTag parses a comma separated tag. A comma inside single quotes or
+// escaped by a backslash doesn't separate elements, so values may contain
+// commas: `name:"'a,b',optional,default='x,y'"`. The specs are cached, as
+// the tags of a program are few and loaded over and over; a spec must not
+// be modified.
+func parseTag(tag string) (tagSpec, error) {
+	cache, _ := tags.Load().(map[string]tagSpec)
+	if spec, ok := cache[tag]; ok {
+		return spec, nil
+	}
+	spec, err := parseTagUncached(tag)
+	if err != nil {
+		return spec, err
+	}
+	tagsMu.Lock()
+	defer tagsMu.Unlock()
+	cache, _ = tags.Load().(map[string]tagSpec)
+	cp := make(map[string]tagSpec, len(cache)+1)
+	for k, v := range cache {
+		cp[k] = v
+	}
+	cp[tag] = spec
+	tags.Store(cp)
+	return spec, nil
+}
+
+func parseTagUncached(tag string) (tagSpec, error) {
+	elems, err := splitTag(tag)
+	if err != nil {
+		return tagSpec{}, fmt.Errorf("invalid tag %q: %v", tag, err)
+	}
+	spec := tagSpec{name: elems[0].text}
+	for _, e := range elems[1:] {
+		if e.key == "" {
+			if spec.flags == nil {
+				spec.flags = make(map[string]bool)
+			}
+			spec.flags[e.text] = true
+			continue
+		}
+		if spec.options == nil {
+			spec.options = make(map[string]string)
+		}
+		spec.options[e.key] = e.text
+	}
+	return spec, nil
+}
+
+// tagElem is an element of a tag, key is set for key=value options.
+type tagElem struct {
+	key  string
+	text string
+}
+
+func splitTag(tag string) ([]tagElem, error) {
+	var (
+		elems  []tagElem
+		cur    strings.Builder
+		key    string
+		quoted bool
+	)
+	for i := 0; i < len(tag); i++ {
+		ch := tag[i]
+		switch {
+		case ch == '\\':
+			if i+1 == len(tag) {
+				return nil, errors.New("trailing backslash")
+			}
+			i++
+			cur.WriteByte(tag[i])
+		case ch == '\'':
+			quoted = !quoted
+		case quoted:
+			cur.WriteByte(ch)
+		case ch == '=' && key == "" && len(elems) > 0:
+			key = strings.TrimSpace(cur.String())
+			if key == "" {
+				return nil, errors.New("option without key")
+			}
+			cur.Reset()
+		case ch == ',':
+			elems = append(elems, tagElem{key: key, text: cur.String()})
+			key = ""
+			cur.Reset()
+		default:
+			cur.WriteByte(ch)
+		}
+	}
+	if quoted {
+		return nil, errors.New("unterminated quote")
+	}
+	return append(elems, tagElem{key: key, text: cur.String()}), nil
+}
@@ -0,0 +1,60 @@
+package keeper
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseTag(t *testing.T) {
+	cases := []struct {
+		tag  string
+		want tagSpec
+	}{
+		{"helloService", tagSpec{name: "helloService"}},
+		{"helloService,optional", tagSpec{name: "helloService", flags: map[string]bool{"optional": true}}},
+		{"'a,b',optional,default='x,y'", tagSpec{
+			name:    "a,b",
+			flags:   map[string]bool{"optional": true},
+			options: map[string]string{"default": "x,y"},
+		}},
+		{`a\,b,default=c\'d`, tagSpec{name: "a,b", options: map[string]string{"default": "c'd"}}},
+		{"db=primary", tagSpec{name: "db=primary"}},
+	}
+	for _, tc := range cases {
+		got, err := parseTag(tc.tag)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tc.tag, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("parse %q: got %+v, want %+v", tc.tag, got, tc.want)
+		}
+	}
+	for _, tag := range []string{"'a", `a\`, "a,=b"} {
+		if _, err := parseTag(tag); err == nil {
+			t.Fatalf("parse %q: expected error", tag)
+		}
+	}
+}
+
+type fallbackCtl struct {
+	primary *HelloSrv     `name:"primaryService,default=helloService"`
+	retries int           `value:"client.retries,default=3"`
+	timeout time.Duration `value:"'client.timeout',default='1s'"`
+}
+
+func TestContainer_TagDefault(t *testing.T) {
+	unsafeOnly(t)
+	c := New(WithConfig(&mapSource{values: map[string]string{"client.timeout": "2s"}}))
+	c.Register(&HelloSrv{word: "fallback"}, Name("helloService"))
+	ctl := new(fallbackCtl)
+	if err := c.Register(ctl, Name("ctl")); err != nil {
+		t.Fatal(err)
+	}
+	if ctl.primary.word != "fallback" || ctl.retries != 3 || ctl.timeout != 2*time.Second {
+		t.Fatalf("got %+v", ctl)
+	}
+	if deps := c.Beans()[1].Dependencies; deps[0] != "helloService" {
+		t.Fatalf("got dependencies %v", deps)
+	}
+}
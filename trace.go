@@ -0,0 +1,57 @@
+package keeper
+
+import "context"
+
+// Tracer starts the spans which make the startup of the container visible in
+// traces. It keeps keeper free of a tracing dependency; an OpenTelemetry
+// tracer is adapted with a few lines:
+//
+//	type otelTracer struct{ trace.Tracer }
+//
+//	func (t otelTracer) StartSpan(ctx context.Context, name string) (context.Context, keeper.Span) {
+//		ctx, span := t.Start(ctx, name)
+//		return ctx, otelSpan{span}
+//	}
+type Tracer interface {
+	StartSpan(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a single traced operation: Register, load or AfterPropertySet of a bean.
+type Span interface {
+	SetAttribute(key, value string)
+	RecordError(err error)
+	End()
+}
+
+// WithTracer is an Option that traces the registration of beans with t.
+func WithTracer(t Tracer) Option {
+	return optionFunc(func(c *Container) {
+		c.tracer = t
+	})
+}
+
+type nopTracer struct{}
+
+func (nopTracer) StartSpan(ctx context.Context, _ string) (context.Context, Span) {
+	return ctx, nopSpan{}
+}
+
+type nopSpan struct{}
+
+func (nopSpan) SetAttribute(string, string) {}
+func (nopSpan) RecordError(error)           {}
+func (nopSpan) End()                        {}
+
+// traced runs fn in a span named op, which is attributed to bean if given.
+func (c *Container) traced(ctx context.Context, op, bean string, fn func(context.Context) error) error {
+	ctx, span := c.tracer.StartSpan(ctx, op)
+	defer span.End()
+	if bean != "" {
+		span.SetAttribute("keeper.bean", bean)
+	}
+	err := fn(ctx)
+	if err != nil {
+		span.RecordError(err)
+	}
+	return err
+}
@@ -0,0 +1,63 @@
+package keeper
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type spanKey struct{}
+
+// recordingTracer records spans as "parent>name(bean)".
+type recordingTracer struct {
+	spans []string
+}
+
+type recordingSpan struct {
+	tracer *recordingTracer
+	path   string
+	bean   string
+	err    error
+}
+
+func (t *recordingTracer) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	path := name
+	if parent, ok := ctx.Value(spanKey{}).(*recordingSpan); ok {
+		path = parent.path + ">" + name
+	}
+	span := &recordingSpan{tracer: t, path: path}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func (s *recordingSpan) SetAttribute(key, value string) { s.bean = value }
+func (s *recordingSpan) RecordError(err error)          { s.err = err }
+func (s *recordingSpan) End() {
+	entry := s.path + "(" + s.bean + ")"
+	if s.err != nil {
+		entry += " error"
+	}
+	s.tracer.spans = append(s.tracer.spans, entry)
+}
+
+type initializedSrv struct{}
+
+func (*initializedSrv) AfterPropertySet() {}
+
+func TestContainer_Tracer(t *testing.T) {
+	tracer := new(recordingTracer)
+	c := New(WithTracer(tracer))
+	if err := c.Register(new(initializedSrv), Name("srv")); err != nil {
+		t.Fatal(err)
+	}
+	c.Register(new(HelloCtl), Name("helloCtl"))
+	want := []string{
+		"keeper.Register>keeper.load>keeper.AfterPropertySet(srv)",
+		"keeper.Register>keeper.load(srv)",
+		"keeper.Register(srv)",
+		"keeper.Register>keeper.load(helloCtl) error",
+		"keeper.Register(helloCtl) error",
+	}
+	if got := strings.Join(tracer.spans, "\n"); got != strings.Join(want, "\n") {
+		t.Fatalf("got spans\n%s", got)
+	}
+}
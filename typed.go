@@ -0,0 +1,229 @@
+package keeper
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FactoryFunc builds a bean, resolving its own dependencies from k.
+type FactoryFunc func(k Keeper) (interface{}, error)
+
+// lazyBean is a bean built by its factory on first resolution, and rebuilt
+// once its ttl expires.
+type lazyBean struct {
+	factory    FactoryFunc
+	ttl        time.Duration
+	serveStale bool // when a rebuild fails
+	mu         sync.Mutex
+	built      bool
+	builtAt    time.Time
+	value      interface{}
+
+	building chan struct{} // closed once the build in flight ends
+}
+
+// errReentrantBuild fails the resolution of a factory bean by its own factory.
+var errReentrantBuild = errors.New("its factory resolves the bean itself")
+
+// buildScope is the Keeper given to the factory of a lazy bean: it resolves
+// beans like the container, and tracks the chain of the builds in progress,
+// so that a factory resolving its own bean, directly or through the factories
+// of its dependencies, fails rather than waits for itself.
+type buildScope struct {
+	*Container
+	lazy   *lazyBean
+	parent *buildScope
+}
+
+// scopeOf returns the scope of the build resolving through k, nil when k
+// isn't the Keeper given to a factory.
+func scopeOf(k Keeper) *buildScope {
+	s, _ := k.(*buildScope)
+	return s
+}
+
+// within reports whether b is being built down the chain of s.
+func (s *buildScope) within(b *lazyBean) bool {
+	for ; s != nil; s = s.parent {
+		if s.lazy == b {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *buildScope) Find(name string) interface{} {
+	return s.Container.find(s, name)
+}
+
+func (s *buildScope) ResolveType(typ reflect.Type) (interface{}, error) {
+	return s.Container.resolveType(s, typ)
+}
+
+// get builds the bean when needed, concurrent callers wait for a single
+// build, while a factory down the chain of scope resolving the bean fails.
+// A failed build is retried on the next call, meanwhile the expired bean is
+// returned if it's served stale. An expired bean which is replaced is
+// destroyed if it's a Disposer.
+func (b *lazyBean) get(c *Container, scope *buildScope) (interface{}, error) {
+	if scope.within(b) {
+		return nil, errReentrantBuild
+	}
+	b.mu.Lock()
+	for b.building != nil {
+		building := b.building
+		b.mu.Unlock()
+		<-building
+		b.mu.Lock()
+	}
+	if b.fresh() {
+		defer b.mu.Unlock()
+		return b.value, nil
+	}
+	building := make(chan struct{})
+	b.building = building
+	b.mu.Unlock()
+
+	v, err := b.build(&buildScope{Container: c, lazy: b, parent: scope})
+
+	b.mu.Lock()
+	b.building = nil
+	close(building)
+	if err != nil {
+		defer b.mu.Unlock()
+		if b.serveStale && b.built {
+			return b.value, nil
+		}
+		return nil, err
+	}
+	old, expired := b.value, b.built
+	b.value, b.built, b.builtAt = v, true, time.Now()
+	b.mu.Unlock()
+	if d, ok := old.(Disposer); ok && expired && old != v {
+		d.Destroy()
+	}
+	return v, nil
+}
+
+// build runs the factory, turning its panic into an error so that the
+// callers waiting for the build are released.
+func (b *lazyBean) build(k Keeper) (v interface{}, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = panicError{value: p}
+		}
+	}()
+	return b.factory(k)
+}
+
+// peek returns the bean if it's built, without building it.
+func (b *lazyBean) peek() interface{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.built {
+		return nil
+	}
+	return b.value
+}
+
+func (b *lazyBean) fresh() bool {
+	return b.built && (b.ttl <= 0 || time.Since(b.builtAt) < b.ttl)
+}
+
+// RegisterTyped registers factory as the provider of the beans of typ, for
+// frameworks generating types at runtime or working over schemas. The bean
+// is built by the first ResolveType of typ, and must be assignable to typ.
+func (c *Container) RegisterTyped(typ reflect.Type, factory FactoryFunc) error {
+	if typ == nil || factory == nil {
+		return fmt.Errorf("cannot register typed factory of %v", typ)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exist := c.typed[typ]; exist {
+		return fmt.Errorf("register duplicate! factory of %v already registered", typ)
+	}
+	c.typed[typ] = &lazyBean{factory: factory}
+	return nil
+}
+
+// ResolveType returns the bean of typ: the one built by the factory of
+// RegisterTyped, or else the only named bean assignable to typ. A bean which
+// requires a capability fails with ErrMissingCapability.
+func (c *Container) ResolveType(typ reflect.Type) (interface{}, error) {
+	return c.resolveType(nil, typ)
+}
+
+// resolveType is ResolveType within scope, when a factory resolves typ.
+func (c *Container) resolveType(scope *buildScope, typ reflect.Type) (interface{}, error) {
+	c.mu.RLock()
+	lazy, ok := c.typed[typ]
+	c.mu.RUnlock()
+	if ok {
+		v, err := lazy.get(c, scope)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build %v: %w", typ, err)
+		}
+		if v == nil || !reflect.TypeOf(v).AssignableTo(typ) {
+			return nil, fmt.Errorf("factory of %v built %T", typ, v)
+		}
+		return c.checkResolved(typ, "", v)
+	}
+	var names []string
+	nodes := c.published()
+	for name, bean := range nodes {
+		switch b := bean.(type) {
+		case *lazyBean:
+			bean = b.peek() // an unbuilt factory bean has no type yet
+		case *beanGroup:
+			continue
+		}
+		if bean != nil && reflect.TypeOf(bean).AssignableTo(typ) {
+			names = append(names, name)
+		}
+	}
+	switch len(names) {
+	case 0:
+		if def := c.defaultFor(typ); def != nil {
+			return c.checkResolved(typ, "", def)
+		}
+		return nil, fmt.Errorf("no bean of type %v", typ)
+	case 1:
+		return c.checkResolved(typ, names[0], c.find(scope, names[0]))
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("ambiguous dependency: %d beans of type %v: %s", len(names), typ, c.candidates(nodes, names))
+}
+
+// checkResolved returns bean, resolved by type as the bean of name, unless
+// it requires a capability: the caller of ResolveType, like BindFunc and the
+// constructors of ProvideConstructor, holds none.
+func (c *Container) checkResolved(typ reflect.Type, name string, bean interface{}) (interface{}, error) {
+	if err := c.checkCapability(name, bean, registerOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to resolve %v: %w", typ, err)
+	}
+	return bean, nil
+}
+
+// candidates describes the beans of names, with their type and registration site.
+func (c *Container) candidates(nodes map[string]interface{}, names []string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	descs := make([]string, len(names))
+	for i, name := range names {
+		bean := nodes[name]
+		if lazy, ok := bean.(*lazyBean); ok {
+			bean = lazy.peek()
+		}
+		desc := fmt.Sprintf("%s (%T", name, bean)
+		if site := c.opts[name].site; site != "" {
+			desc += " registered at " + site
+		}
+		descs[i] = desc + ")"
+	}
+	return strings.Join(descs, ", ")
+}
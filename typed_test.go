@@ -0,0 +1,61 @@
+package keeper
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type schemaRepo struct {
+	table string
+}
+
+func TestContainer_RegisterTyped(t *testing.T) {
+	c := New()
+	typ := reflect.TypeOf((*schemaRepo)(nil))
+	builds := 0
+	err := c.RegisterTyped(typ, func(Keeper) (interface{}, error) {
+		builds++
+		return &schemaRepo{table: "users"}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.RegisterTyped(typ, nil); err == nil {
+		t.Fatal("expected error for invalid factory")
+	}
+	for i := 0; i < 2; i++ {
+		v, err := c.ResolveType(typ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.(*schemaRepo).table != "users" {
+			t.Fatalf("got %+v", v)
+		}
+	}
+	if builds != 1 {
+		t.Fatalf("factory ran %d times", builds)
+	}
+}
+
+func TestContainer_ResolveTypeByName(t *testing.T) {
+	c := New()
+	greeter := reflect.TypeOf((*Greeter)(nil)).Elem()
+	if _, err := c.ResolveType(greeter); err == nil {
+		t.Fatal("expected error without beans")
+	}
+	c.Register(new(HelloSrv), Name("helloService"))
+	v, err := c.ResolveType(greeter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != c.Find("helloService") {
+		t.Fatal("expected helloService")
+	}
+	c.Register(new(HelloSrv), Name("backupService"))
+	_, err = c.ResolveType(greeter)
+	if err == nil || !strings.Contains(err.Error(), "backupService (*keeper.HelloSrv registered at ") ||
+		!strings.Contains(err.Error(), "typed_test.go:55), helloService (") {
+		t.Fatalf("expected ambiguity listing the candidates, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrNotFound is the error of KeeperV2 when a bean isn't registered.
+var ErrNotFound = errors.New("bean not found")
+
+// KeeperV2 is the context-first interface of a container, whose methods
+// report failures as errors. It's the interface new methods are added to,
+// Keeper keeps its signatures for existing users. Both are views of the
+// same container, beans registered by one are found by the other.
+type KeeperV2 interface {
+	// resolve the bean of name, an ErrNotFound error if there is none
+	Resolve(ctx context.Context, name string) (interface{}, error)
+	// resolve the bean of a type
+	ResolveType(ctx context.Context, typ reflect.Type) (interface{}, error)
+	// register a bean, its ctx tagged fields are set from ctx
+	Register(ctx context.Context, bean interface{}, opts ...RegisterOption) error
+	// inject the dependencies and ctx values of ptr, but not register it
+	Provide(ctx context.Context, ptr interface{}) error
+	// start the beans and the workers
+	Start(ctx context.Context) error
+	// stop the workers and dispose the beans, giving up waiting when ctx is done
+	Close(ctx context.Context) error
+	// the legacy interface of the container
+	V1() Keeper
+}
+
+// V2 returns the KeeperV2 interface of the container.
+func (c *Container) V2() KeeperV2 {
+	return containerV2{c: c}
+}
+
+type containerV2 struct {
+	c *Container
+}
+
+func (v containerV2) Resolve(ctx context.Context, name string) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	bean := v.c.lookup(name)
+	if bean == nil {
+		return nil, fmt.Errorf("%s: %w, %s", name, ErrNotFound, v.c.suggest(name))
+	}
+	return bean, nil
+}
+
+func (v containerV2) ResolveType(ctx context.Context, typ reflect.Type) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return v.c.ResolveType(typ)
+}
+
+func (v containerV2) Register(ctx context.Context, bean interface{}, opts ...RegisterOption) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	var options registerOptions
+	for _, o := range opts {
+		o.applyRegisterOption(&options)
+	}
+	if err := options.Validate(); err != nil {
+		return err
+	}
+	return v.c.registerContext(ctx, bean, options)
+}
+
+func (v containerV2) Provide(ctx context.Context, ptr interface{}) error {
+	return v.c.ProvideContext(ctx, ptr)
+}
+
+func (v containerV2) Start(ctx context.Context) error {
+	return v.c.Start(ctx)
+}
+
+func (v containerV2) Close(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- v.c.Close()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (v containerV2) V1() Keeper {
+	return v.c
+}
@@ -0,0 +1,44 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type tenantScoped struct {
+	tenant string `ctx:"tenant"`
+}
+
+func TestContainer_V2(t *testing.T) {
+	unsafeOnly(t)
+	k := New().V2()
+	ctx := WithValue(context.Background(), "tenant", "acme")
+	scoped := new(tenantScoped)
+	if err := k.Register(ctx, scoped, Name("tenant")); err != nil {
+		t.Fatal(err)
+	}
+	if scoped.tenant != "acme" {
+		t.Fatalf("got tenant %q", scoped.tenant)
+	}
+	bean, err := k.Resolve(ctx, "tenant")
+	if err != nil || bean != scoped {
+		t.Fatalf("got %v, %v", bean, err)
+	}
+	if k.V1().Find("tenant") != scoped {
+		t.Fatal("V1 should share the beans of V2")
+	}
+	_, err = k.Resolve(ctx, "tenants")
+	if !errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), `did you mean "tenant"?`) {
+		t.Fatalf("got %v", err)
+	}
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := k.Resolve(cancelled, "tenant"); err != context.Canceled {
+		t.Fatalf("got %v", err)
+	}
+	if err := k.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
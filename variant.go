@@ -0,0 +1,70 @@
+package keeper
+
+import "strings"
+
+// Variant is a set of registrations made only when one of its profiles is
+// active, declared with When. Declaring the variants of every environment in
+// one place shows what runs where, unlike conditions scattered around Register:
+//
+//	err := c.RegisterVariants(
+//		keeper.Always(keeper.Bean(new(Service), keeper.Name("service"))),
+//		keeper.When("prod", keeper.Bean(new(KafkaSink), keeper.Name("sink"))),
+//		keeper.When("dev|test", keeper.Bean(new(StdoutSink), keeper.Name("sink"))),
+//	)
+//
+// The profiles come from WithProfiles, which may be given a constant set by
+// a file of each build tag to select the variants at compile time.
+type Variant struct {
+	Profiles      []string // empty for every profile
+	Registrations []Registration
+}
+
+// When returns the Variant of regs for profile, which may list several
+// profiles separated by '|' like ifprofile.
+func When(profile string, regs ...Registration) Variant {
+	return Variant{Profiles: strings.Split(profile, "|"), Registrations: regs}
+}
+
+// Always returns the Variant of regs for every profile.
+func Always(regs ...Registration) Variant {
+	return Variant{Registrations: regs}
+}
+
+// active reports whether the variant is selected by profiles.
+func (v Variant) active(profiles map[string]bool) bool {
+	if len(v.Profiles) == 0 {
+		return true
+	}
+	for _, p := range v.Profiles {
+		if profiles[p] {
+			return true
+		}
+	}
+	return false
+}
+
+// Select returns the registrations of the variants selected by profiles, in
+// the order they're declared, the final graph RegisterVariants would register.
+func Select(profiles []string, variants ...Variant) []Registration {
+	active := make(map[string]bool, len(profiles))
+	for _, p := range profiles {
+		active[p] = true
+	}
+	return selectVariants(active, variants)
+}
+
+func selectVariants(profiles map[string]bool, variants []Variant) []Registration {
+	var regs []Registration
+	for _, v := range variants {
+		if v.active(profiles) {
+			regs = append(regs, v.Registrations...)
+		}
+	}
+	return regs
+}
+
+// RegisterVariants registers the registrations of the variants selected by
+// the profiles of the container like RegisterBatch.
+func (c *Container) RegisterVariants(variants ...Variant) error {
+	return c.RegisterBatch(selectVariants(c.profiles, variants)...)
+}
@@ -0,0 +1,58 @@
+package keeper
+
+import (
+	"reflect"
+	"testing"
+)
+
+type kafkaSink struct{}
+type stdoutSink struct{}
+
+type sinkService struct {
+	sink interface{} `name:"sink"`
+}
+
+func variantsOf() []Variant {
+	return []Variant{
+		When("prod", Bean(new(kafkaSink), Name("sink"))),
+		When("dev|test", Bean(new(stdoutSink), Name("sink"))),
+		Always(Bean(new(sinkService), Name("service"))),
+	}
+}
+
+func TestSelect(t *testing.T) {
+	names := func(regs []Registration) []string {
+		var names []string
+		for _, reg := range regs {
+			var options registerOptions
+			for _, o := range reg.Options {
+				o.applyRegisterOption(&options)
+			}
+			names = append(names, options.Name+":"+reflect.TypeOf(reg.Bean).Elem().Name())
+		}
+		return names
+	}
+	for _, tt := range []struct {
+		profiles []string
+		want     []string
+	}{
+		{[]string{"prod"}, []string{"sink:kafkaSink", "service:sinkService"}},
+		{[]string{"test"}, []string{"sink:stdoutSink", "service:sinkService"}},
+		{[]string{"staging"}, []string{"service:sinkService"}},
+	} {
+		if got := names(Select(tt.profiles, variantsOf()...)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v: got %v, want %v", tt.profiles, got, tt.want)
+		}
+	}
+}
+
+func TestRegisterVariants(t *testing.T) {
+	unsafeOnly(t)
+	c := New(WithProfiles("dev"))
+	if err := c.RegisterVariants(variantsOf()...); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Find("service").(*sinkService).sink.(*stdoutSink); !ok {
+		t.Errorf("got %T, want the dev sink", c.Find("service").(*sinkService).sink)
+	}
+}
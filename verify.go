@@ -0,0 +1,187 @@
+package keeper
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// WiringError is a dependency which can't be injected. It marshals to JSON,
+// so CI pipelines and editors could render wiring problems.
+type WiringError struct {
+	Bean       string `json:"bean"`
+	Field      string `json:"field"`
+	Dependency string `json:"dependency,omitempty"`
+	Expected   string `json:"expected,omitempty"` // type of the field
+	Got        string `json:"got"`                // what was found instead
+	Suggestion string `json:"suggestion,omitempty"`
+	Site       string `json:"site,omitempty"` // file:line of the registration of Bean
+}
+
+func (e *WiringError) Error() string {
+	msg := fmt.Sprintf("%s.%s", e.Bean, e.Field)
+	if e.Dependency != "" {
+		msg += fmt.Sprintf(" <- %s", e.Dependency)
+	}
+	if e.Expected != "" {
+		msg += fmt.Sprintf(": expected %s, got %s", e.Expected, e.Got)
+	} else {
+		msg += ": " + e.Got
+	}
+	if e.Suggestion != "" {
+		msg += " (" + e.Suggestion + ")"
+	}
+	if e.Site != "" {
+		msg += " (registered at " + e.Site + ")"
+	}
+	return msg
+}
+
+// WiringErrors is every WiringError found by Build or Verify.
+type WiringErrors []*WiringError
+
+func (errs WiringErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d wiring errors: %s", len(errs), strings.Join(msgs, "; "))
+}
+
+// Verify checks, without injecting anything, that the dependencies deferred
+// by MissingDefer are now available, and that the name tagged fields of
+// beans, pointers to structs which aren't registered, could be injected.
+// Problems are returned as WiringErrors.
+func (c *Container) Verify(beans ...interface{}) error {
+	var errs WiringErrors
+	c.mu.RLock()
+	deferred := append([]deferredField(nil), c.deferred...)
+	c.mu.RUnlock()
+	for _, d := range deferred {
+		if werr := c.checkField(d.bean, reflect.TypeOf(d.ptr).Elem(), d.field, d.options); werr != nil {
+			errs = append(errs, werr)
+		}
+	}
+	for _, bean := range beans {
+		typ := reflect.TypeOf(bean)
+		if typ == nil || typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct {
+			errs = append(errs, &WiringError{Bean: fmt.Sprint(typ), Got: "not a pointer to struct"})
+			continue
+		}
+		if _, opaque := bean.(NoInjector); opaque {
+			continue
+		}
+		typ = typ.Elem()
+		for i := 0; i < typ.NumField(); i++ {
+			if _, ok := typ.Field(i).Tag.Lookup(_nameTag); !ok {
+				continue
+			}
+			if werr := c.checkField(typ.Name(), typ, i, noopRegisterOption); werr != nil {
+				errs = append(errs, werr)
+			}
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// checkField reports why the name tagged i-th field of typ couldn't be
+// injected, or nil if it could.
+func (c *Container) checkField(bean string, typ reflect.Type, i int, options registerOptions) *WiringError {
+	tv := typ.Field(i)
+	werr := &WiringError{Bean: bean, Field: tv.Name, Expected: tv.Type.String(), Site: options.site}
+	spec, err := parseTag(tv.Tag.Get(_nameTag))
+	if err == nil {
+		spec, err = c.expandSpec(spec)
+	}
+	if err != nil {
+		werr.Got = err.Error()
+		return werr
+	}
+	werr.Dependency = spec.name
+	if !c.profileActive(spec) {
+		return nil
+	}
+	elem, name, path := c.resolvePath(spec.name)
+	if fallback, ok := spec.option(_defaultOption); ok && elem == nil {
+		elem, name, path = c.resolvePath(fallback)
+	}
+	if elem == nil {
+		if spec.flag(_optionalTag) || c.defaultFor(tv.Type) != nil {
+			return nil
+		}
+		werr.Got = "nothing"
+		if name := c.closestName(spec.name); name != "" {
+			werr.Suggestion = fmt.Sprintf("did you mean %q?", name)
+		}
+		return werr
+	}
+	if err := c.checkCapability(name, elem, options); err != nil {
+		werr.Got = err.Error()
+		return werr
+	}
+	if path != "" {
+		if _, ok, err := subValue(tv.Type, elem, path); err != nil || !ok {
+			if werr.Got = "nothing at " + path; err != nil {
+				werr.Got = err.Error()
+			}
+			return werr
+		}
+		return nil
+	}
+	if _, err := c.assign(tv.Type, elem); err != nil {
+		werr.Got = reflect.TypeOf(elem).String()
+		if strings.Contains(err.Error(), "AllowConversion") {
+			werr.Suggestion = "use the AllowConversion option"
+		} else if reflect.TypeOf(elem).Kind() == reflect.Ptr && reflect.PtrTo(tv.Type).AssignableTo(reflect.TypeOf(elem)) {
+			werr.Suggestion = "declare the field as " + reflect.TypeOf(elem).String()
+		}
+		return werr
+	}
+	return nil
+}
+
+// closestName returns the registered name closest to name, if it's a likely
+// typo. Ties are broken by name, so the suggestion is stable.
+func (c *Container) closestName(name string) string {
+	best, bestDist := "", len(name)/2+1
+	for candidate := range c.published() {
+		if d := editDistance(name, candidate); d < bestDist || d == bestDist && best != "" && candidate < best {
+			best, bestDist = candidate, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
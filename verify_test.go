@@ -0,0 +1,72 @@
+package keeper
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type typoCtl struct {
+	helloSrv *HelloSrv `name:"helloServce"`
+	ctl      *HelloSrv `name:"helloCtl"`
+}
+
+func TestContainer_Verify(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	c.Register(new(HelloSrv), Name("helloService"))
+	c.Register(new(HelloCtl), Name("helloCtl"))
+	if err := c.Verify(new(HelloCtl)); err != nil {
+		t.Fatal(err)
+	}
+	err := c.Verify(new(typoCtl))
+	errs, ok := err.(WiringErrors)
+	if !ok || len(errs) != 2 {
+		t.Fatalf("got %v", err)
+	}
+	if errs[0].Dependency != "helloServce" || errs[0].Got != "nothing" || errs[0].Suggestion != `did you mean "helloService"?` {
+		t.Fatalf("got %+v", errs[0])
+	}
+	if errs[1].Expected != "*keeper.HelloSrv" || errs[1].Got != "*keeper.HelloCtl" {
+		t.Fatalf("got %+v", errs[1])
+	}
+	data, err := json.Marshal(errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded []WiringError
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded[0].Field != "helloSrv" {
+		t.Fatalf("got %s", data)
+	}
+}
+
+func TestContainer_BuildWiringErrors(t *testing.T) {
+	unsafeOnly(t)
+	c := New(OnMissing(MissingDefer))
+	c.Register(new(HelloCtl), Name("helloCtl"))
+	if err := c.Verify(); err == nil {
+		t.Fatal("Verify should report the deferred dependency")
+	}
+	errs, ok := c.Build().(WiringErrors)
+	if !ok || errs[0].Bean != "helloCtl" || errs[0].Dependency != "helloService" {
+		t.Fatalf("got %v", errs)
+	}
+	c.Register(new(HelloSrv), Name("helloService"))
+	if err := c.Build(); err != nil {
+		t.Fatalf("a later Build should retry: %v", err)
+	}
+}
+
+func TestContainer_ClosestNameTie(t *testing.T) {
+	for i := 0; i < 20; i++ { // map iteration order varies
+		c := New()
+		for _, name := range []string{"cacheD", "cacheB", "cacheC"} {
+			c.Register(&HelloSrv{}, Name(name))
+		}
+		_, err := c.V2().Resolve(context.Background(), "cacheX")
+		if err == nil || !strings.Contains(err.Error(), `did you mean "cacheB"?`) {
+			t.Fatalf("got %v, want the first of the equally close names", err)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+)
+
+// View returns a Keeper which finds, resolves and injects the beans of the
+// container, but rejects with ErrReadOnly whatever would change the graph or
+// run its lifecycle: registrations, swaps, merges, Build, Start and Close.
+// It's the Keeper to hand to plugins and other code which must only consume
+// beans. Every method is spelled out, so that a method added to Keeper
+// must be classified here.
+func (c *Container) View() Keeper {
+	return readOnlyView{c: c}
+}
+
+type readOnlyView struct {
+	c *Container
+}
+
+func readOnly(op string) error {
+	return fmt.Errorf("cannot %s through a view: %w", op, ErrReadOnly)
+}
+
+func (v readOnlyView) Find(name string) interface{} { return v.c.Find(name) }
+
+func (v readOnlyView) All() map[string]interface{} { return v.c.All() }
+
+func (v readOnlyView) FindMatching(pattern string) (map[string]interface{}, error) {
+	return v.c.FindMatching(pattern)
+}
+
+func (v readOnlyView) FindRegexp(re *regexp.Regexp) map[string]interface{} {
+	return v.c.FindRegexp(re)
+}
+
+func (v readOnlyView) Provider(ptr interface{}) error { return v.c.Provider(ptr) }
+
+func (v readOnlyView) Register(interface{}, ...RegisterOption) error { return readOnly("Register") }
+
+func (v readOnlyView) Start(context.Context) error { return readOnly("Start") }
+
+func (v readOnlyView) Migrate(context.Context) error { return readOnly("Migrate") }
+
+func (v readOnlyView) Close() error { return readOnly("Close") }
+
+func (v readOnlyView) Beans() []BeanInfo { return v.c.Beans() }
+
+func (v readOnlyView) Adopt(string, interface{}, ...RegisterOption) error { return readOnly("Adopt") }
+
+func (v readOnlyView) RegisterBatch(...Registration) error { return readOnly("RegisterBatch") }
+func (v readOnlyView) RegisterVariants(...Variant) error   { return readOnly("RegisterVariants") }
+
+func (v readOnlyView) Build() error { return readOnly("Build") }
+
+func (v readOnlyView) BuildFor(...string) error { return readOnly("BuildFor") }
+
+func (v readOnlyView) Verify(beans ...interface{}) error { return v.c.Verify(beans...) }
+
+func (v readOnlyView) RegisterTyped(reflect.Type, FactoryFunc) error {
+	return readOnly("RegisterTyped")
+}
+
+func (v readOnlyView) ResolveType(typ reflect.Type) (interface{}, error) {
+	return v.c.ResolveType(typ)
+}
+
+func (v readOnlyView) RegisterFactory(FactoryFunc, ...RegisterOption) error {
+	return readOnly("RegisterFactory")
+}
+
+func (v readOnlyView) ProvideContext(ctx context.Context, ptr interface{}) error {
+	return v.c.ProvideContext(ctx, ptr)
+}
+
+func (v readOnlyView) Swap(string, interface{}) (interface{}, error) { return nil, readOnly("Swap") }
+
+func (v readOnlyView) RegisterDefaultFor(interface{}, interface{}) error {
+	return readOnly("RegisterDefaultFor")
+}
+
+func (v readOnlyView) Doctor() []Finding { return v.c.Doctor() }
+
+func (v readOnlyView) DumpState(w io.Writer) error { return v.c.DumpState(w) }
+
+func (v readOnlyView) Stats() []BuildStats { return v.c.Stats() }
+
+func (v readOnlyView) Install(...Module) error { return readOnly("Install") }
+
+func (v readOnlyView) LoadPlugin(string) error { return readOnly("LoadPlugin") }
+
+func (v readOnlyView) Merge(Keeper, ConflictPolicy) error { return readOnly("Merge") }
+
+func (v readOnlyView) RunPhase(context.Context, string) error { return readOnly("RunPhase") }
+
+func (v readOnlyView) FieldStats() []FieldStats { return v.c.FieldStats() }
+
+func (v readOnlyView) Jobs() []JobStats { return v.c.Jobs() }
+
+func (v readOnlyView) ExportSpec() Spec { return v.c.ExportSpec() }
+
+func (v readOnlyView) Range(fn func(name string, bean interface{}) bool) { v.c.Range(fn) }
+
+func (v readOnlyView) AllWithPrefix(prefix string) map[string]interface{} {
+	return v.c.AllWithPrefix(prefix)
+}
+
+func (v readOnlyView) AllLabeled(key, value string) map[string]interface{} {
+	return v.c.AllLabeled(key, value)
+}
+
+func (v readOnlyView) V2() KeeperV2 { return readOnlyViewV2{v} }
+
+func (v readOnlyView) ProvideConstructor(interface{}) error { return readOnly("ProvideConstructor") }
+
+func (v readOnlyView) View() Keeper { return v }
+
+func (v readOnlyView) AuditLog() string { return v.c.AuditLog() }
+
+func (v readOnlyView) Warm(ctx context.Context, names ...string) error {
+	return v.c.Warm(ctx, names...)
+}
+
+func (v readOnlyView) Fingerprint() string { return v.c.Fingerprint() }
+
+func (v readOnlyView) Seal() error { return readOnly("Seal") }
+
+func (v readOnlyView) Reset() error { return readOnly("Reset") }
+
+func (v readOnlyView) AssertTypes(expected map[string]reflect.Type) error {
+	return v.c.AssertTypes(expected)
+}
+
+// readOnlyViewV2 is the KeeperV2 interface of a View.
+type readOnlyViewV2 struct {
+	v readOnlyView
+}
+
+func (v readOnlyViewV2) Resolve(ctx context.Context, name string) (interface{}, error) {
+	return v.v.c.V2().Resolve(ctx, name)
+}
+
+func (v readOnlyViewV2) ResolveType(ctx context.Context, typ reflect.Type) (interface{}, error) {
+	return v.v.c.V2().ResolveType(ctx, typ)
+}
+
+func (v readOnlyViewV2) Register(context.Context, interface{}, ...RegisterOption) error {
+	return readOnly("Register")
+}
+
+func (v readOnlyViewV2) Provide(ctx context.Context, ptr interface{}) error {
+	return v.v.c.ProvideContext(ctx, ptr)
+}
+
+func (v readOnlyViewV2) Start(context.Context) error { return readOnly("Start") }
+
+func (v readOnlyViewV2) Close(context.Context) error { return readOnly("Close") }
+
+func (v readOnlyViewV2) V1() Keeper { return v.v }
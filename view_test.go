@@ -0,0 +1,37 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestContainer_View(t *testing.T) {
+	unsafeOnly(t)
+	c := New()
+	srv := &HelloSrv{word: "hi"}
+	c.Register(srv, Name("helloService"))
+	view := c.View()
+	if view.Find("helloService") != srv || len(view.All()) != 1 {
+		t.Fatal("view should find the beans of the container")
+	}
+	ctl := new(HelloCtl)
+	if err := view.Provider(ctl); err != nil || ctl.helloSrv.word != "hi" {
+		t.Fatalf("view should inject, got %v", err)
+	}
+	if err := view.Register(&HelloSrv{}, Name("otherService")); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("got %v", err)
+	}
+	if _, err := view.Swap("helloService", &HelloSrv{}); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("got %v", err)
+	}
+	if err := view.V2().Register(context.Background(), &HelloSrv{}, Name("otherService")); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("got %v", err)
+	}
+	if err := view.V2().V1().Close(); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("got %v", err)
+	}
+	if c.Find("otherService") != nil || c.Find("helloService") != srv {
+		t.Fatal("view should not modify the container")
+	}
+}
@@ -0,0 +1,76 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Backoff paces the retries of a failed operation.
+type Backoff struct {
+	// Attempts limits the attempts, zero or negative meaning no limit.
+	Attempts int
+	// Min is the delay before the first retry, doubled on each following
+	// retry up to Max.
+	Min time.Duration
+	Max time.Duration
+}
+
+// DefaultBackoff retries up to 10 times, waiting up to 5 seconds in between.
+var DefaultBackoff = Backoff{
+	Attempts: 10,
+	Min:      100 * time.Millisecond,
+	Max:      5 * time.Second,
+}
+
+// readiness is an external resource a bean waits for.
+type readiness struct {
+	probe   func(ctx context.Context) error
+	backoff Backoff
+}
+
+// WaitFor is a RegisterOption that makes Register wait until probe succeeds
+// before the bean is injected and initialized, for beans needing an
+// external resource, like a reachable database or a finished migration.
+// probe is retried with backoff, and Register fails with its last error
+// once the attempts are exhausted. It may be given several times, the
+// probes are waited for in order.
+//
+//	c.Register(repo, keeper.Name("repo"), keeper.WaitFor(db.PingContext, keeper.DefaultBackoff))
+func WaitFor(probe func(ctx context.Context) error, backoff Backoff) RegisterOption {
+	return registerOptionFunc(func(options *registerOptions) {
+		options.WaitFor = append(options.WaitFor, readiness{probe: probe, backoff: backoff})
+	})
+}
+
+// await waits for the readiness of the bean registered with options.
+func (c *Container) await(ctx context.Context, options registerOptions) error {
+	for _, r := range options.WaitFor {
+		if err := c.awaitOne(ctx, options.Name, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Container) awaitOne(ctx context.Context, name string, r readiness) error {
+	delay := r.backoff.Min
+	for attempt := 1; ; attempt++ {
+		err := r.probe(ctx)
+		if err == nil {
+			return nil
+		}
+		if r.backoff.Attempts > 0 && attempt >= r.backoff.Attempts {
+			return fmt.Errorf("%s isn't ready after %d attempts: %w", name, attempt, err)
+		}
+		c.logger.Printf("keeper: %s isn't ready: %v, retrying in %v", name, err, delay)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s isn't ready: %w", name, ctx.Err())
+		case <-time.After(delay):
+		}
+		if delay *= 2; delay > r.backoff.Max {
+			delay = r.backoff.Max
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestContainer_WaitFor(t *testing.T) {
+	c := New()
+	probes := 0
+	ping := func(context.Context) error {
+		if probes++; probes < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+	backoff := Backoff{Attempts: 5, Min: time.Millisecond, Max: time.Millisecond}
+	if err := c.Register(&HelloSrv{}, Name("helloService"), WaitFor(ping, backoff)); err != nil {
+		t.Fatal(err)
+	}
+	if probes != 3 {
+		t.Fatalf("got %d probes", probes)
+	}
+
+	down := func(context.Context) error { return errors.New("connection refused") }
+	err := c.Register(&HelloSrv{}, Name("otherService"), WaitFor(down, Backoff{Attempts: 2}))
+	if err == nil || !strings.Contains(err.Error(), "otherService isn't ready after 2 attempts: connection refused") {
+		t.Fatalf("got %v", err)
+	}
+	if c.Find("otherService") != nil {
+		t.Fatal("bean which isn't ready should not be registered")
+	}
+}
@@ -0,0 +1,99 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// WarmProgress reports a factory bean built by Warm.
+type WarmProgress struct {
+	Bean     string
+	Done     int // beans built so far, this one included
+	Total    int
+	Duration time.Duration
+	Err      error
+}
+
+// OnWarm is an Option that invokes hook after each bean Warm builds, from
+// the goroutine which built it.
+func OnWarm(hook func(WarmProgress)) Option {
+	return optionFunc(func(c *Container) {
+		c.warmHooks = append(c.warmHooks, hook)
+	})
+}
+
+// Warm builds the factory beans of names in parallel, all of them when no
+// name is given, so that they're ready before the instance takes traffic,
+// like before it registers to its load balancer. Beans which are built
+// already, and beans which aren't factory beans, are left alone. When ctx is
+// done, Warm returns its error without waiting for the builds in flight,
+// which carry on. With WithSeed, the beans are built one at a time.
+func (c *Container) Warm(ctx context.Context, names ...string) error {
+	nodes := c.published()
+	if len(names) == 0 {
+		c.mu.RLock()
+		names = append(names, c.order...)
+		c.mu.RUnlock()
+	}
+	lazies := make(map[string]*lazyBean)
+	var order []string
+	for _, name := range names {
+		bean, ok := nodes[name]
+		if !ok {
+			return fmt.Errorf("cannot warm %s, it isn't registered", name)
+		}
+		if lazy, ok := bean.(*lazyBean); ok && lazy.peek() == nil && lazies[name] == nil {
+			lazies[name] = lazy
+			order = append(order, name)
+		}
+	}
+	c.mu.RLock()
+	hooks := c.warmHooks
+	c.mu.RUnlock()
+
+	var (
+		mu   sync.Mutex
+		done int
+		errs multiError
+	)
+	finished := make(chan struct{})
+	var wg sync.WaitGroup
+	if c.rand != nil { // one at a time, in the order of the seed
+		c.rand.shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+	}
+	for _, name := range order {
+		wg.Add(1)
+		warm := func(name string, lazy *lazyBean) {
+			defer wg.Done()
+			begin := time.Now()
+			_, err := c.buildErr(nil, name, lazy)
+			mu.Lock()
+			done++
+			p := WarmProgress{Bean: name, Done: done, Total: len(order), Duration: time.Since(begin), Err: err}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to warm %s: %v", name, err))
+			}
+			mu.Unlock()
+			for _, hook := range hooks {
+				hook(p)
+			}
+		}
+		if c.rand != nil {
+			warm(name, lazies[name])
+		} else {
+			go warm(name, lazies[name])
+		}
+	}
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-finished:
+	}
+	return errs.errOrNil()
+}
@@ -0,0 +1,66 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWarm(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		progress []WarmProgress
+	)
+	c := New(OnWarm(func(p WarmProgress) {
+		mu.Lock()
+		defer mu.Unlock()
+		progress = append(progress, p)
+	}))
+	release := make(chan struct{})
+	var started sync.WaitGroup
+	started.Add(2)
+	slow := func(Keeper) (interface{}, error) {
+		started.Done()
+		<-release
+		return new(HelloSrv), nil
+	}
+	c.RegisterFactory(slow, Name("a"))
+	c.RegisterFactory(slow, Name("b"))
+	c.Register(new(HelloSrv), Name("plain"))
+	go func() {
+		started.Wait() // both builds run in parallel
+		close(release)
+	}()
+	if err := c.Warm(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(progress) != 2 || progress[1].Done != 2 || progress[1].Total != 2 {
+		t.Errorf("got progress %+v", progress)
+	}
+	if err := c.Warm(context.Background(), "a"); err != nil || len(progress) != 2 {
+		t.Errorf("built beans should be left alone, got %v", err)
+	}
+}
+
+func TestWarmErrors(t *testing.T) {
+	c := New()
+	c.RegisterFactory(func(Keeper) (interface{}, error) { return nil, errors.New("no db") }, Name("db"))
+	if err := c.Warm(context.Background(), "db"); err == nil || !strings.Contains(err.Error(), "failed to warm db: no db") {
+		t.Errorf("got %v", err)
+	}
+	if err := c.Warm(context.Background(), "nothing"); err == nil {
+		t.Error("warming an unknown bean should fail")
+	}
+
+	block := make(chan struct{})
+	defer close(block)
+	c.RegisterFactory(func(Keeper) (interface{}, error) { <-block; return new(HelloSrv), nil }, Name("stuck"))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := c.Warm(ctx, "stuck"); err != context.DeadlineExceeded {
+		t.Errorf("got %v, want the deadline", err)
+	}
+}
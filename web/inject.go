@@ -0,0 +1,101 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/tooky0630/keeper"
+)
+
+var (
+	_writerType  = reflect.TypeOf((*http.ResponseWriter)(nil)).Elem()
+	_requestType = reflect.TypeOf((*http.Request)(nil))
+	_contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	_errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// HandlerOption configures Inject.
+type HandlerOption func(*handlerOptions)
+
+type handlerOptions struct {
+	perRequest bool
+}
+
+// PerRequest is a HandlerOption resolving the beans on every request rather
+// than once by Inject, for beans which are swapped or built by factories with a TTL.
+func PerRequest() HandlerOption {
+	return func(o *handlerOptions) {
+		o.perRequest = true
+	}
+}
+
+// Inject turns fn, a func whose parameters are beans of k besides the usual
+// http.ResponseWriter, *http.Request and context.Context of the request,
+// into an http.HandlerFunc. Beans are resolved by type with ResolveType. fn
+// may return an error, which is answered with 500 Internal Server Error.
+//
+//	h, err := web.Inject(c, func(w http.ResponseWriter, r *http.Request, repo *Repo) error {
+//		...
+//	})
+func Inject(k keeper.Keeper, fn interface{}, opts ...HandlerOption) (http.HandlerFunc, error) {
+	var options handlerOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	fv := reflect.ValueOf(fn)
+	ft := fv.Type()
+	if ft.Kind() != reflect.Func {
+		return nil, fmt.Errorf("cannot inject %v, it isn't a func", ft)
+	}
+	if ft.NumOut() > 1 || ft.NumOut() == 1 && ft.Out(0) != _errorType {
+		return nil, fmt.Errorf("cannot inject %v, it may only return an error", ft)
+	}
+	resolve := func() ([]reflect.Value, error) {
+		beans := make([]reflect.Value, ft.NumIn())
+		for i := range beans {
+			switch typ := ft.In(i); typ {
+			case _writerType, _requestType, _contextType:
+			default:
+				bean, err := k.ResolveType(typ)
+				if err != nil {
+					return nil, fmt.Errorf("cannot inject parameter %d of %v: %v", i, ft, err)
+				}
+				beans[i] = reflect.ValueOf(bean)
+			}
+		}
+		return beans, nil
+	}
+	beans, err := resolve()
+	if err != nil {
+		return nil, err
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		args := beans
+		if options.perRequest {
+			var err error
+			if args, err = resolve(); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		in := make([]reflect.Value, len(args))
+		for i, arg := range args {
+			switch ft.In(i) {
+			case _writerType:
+				in[i] = reflect.ValueOf(&w).Elem()
+			case _requestType:
+				in[i] = reflect.ValueOf(r)
+			case _contextType:
+				in[i] = reflect.ValueOf(r.Context())
+			default:
+				in[i] = arg
+			}
+		}
+		out := fv.Call(in)
+		if len(out) == 1 && !out[0].IsNil() {
+			http.Error(w, out[0].Interface().(error).Error(), http.StatusInternalServerError)
+		}
+	}, nil
+}
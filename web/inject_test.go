@@ -0,0 +1,57 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tooky0630/keeper"
+)
+
+type greeter struct {
+	word string
+}
+
+func TestInject(t *testing.T) {
+	c := keeper.New()
+	c.Register(&greeter{word: "hello"}, keeper.Name("greeter"))
+	h, err := Inject(c, func(ctx context.Context, w http.ResponseWriter, g *greeter, r *http.Request) {
+		w.Write([]byte(g.word + " " + r.URL.Query().Get("name")))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodGet, "/?name=keeper", nil))
+	if rec.Body.String() != "hello keeper" {
+		t.Fatalf("got %q", rec.Body)
+	}
+}
+
+func TestInjectPerRequest(t *testing.T) {
+	c := keeper.New()
+	c.Register(&greeter{word: "hello"}, keeper.Name("greeter"))
+	h, _ := Inject(c, func(w http.ResponseWriter, g *greeter) error {
+		if g.word != "hello" {
+			return errors.New("swapped")
+		}
+		return nil
+	}, PerRequest())
+	c.Swap("greeter", &greeter{word: "bye"})
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusInternalServerError || rec.Body.String() != "swapped\n" {
+		t.Fatalf("got %d %q", rec.Code, rec.Body)
+	}
+}
+
+func TestInjectMissing(t *testing.T) {
+	if _, err := Inject(keeper.New(), func(*greeter) {}); err == nil {
+		t.Fatal("expected error for a missing bean")
+	}
+	if _, err := Inject(keeper.New(), "handler"); err == nil {
+		t.Fatal("expected error for a non func")
+	}
+}
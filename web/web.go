@@ -0,0 +1,134 @@
+// Package web mounts the routes of keeper managed controllers onto a router.
+//
+// Controllers implement RouteProvider, and a Router registered into the same
+// container collects and mounts all of them when the container starts:
+//
+//	c := keeper.New()
+//	c.Register(web.NewRouter(c, http.NewServeMux()), keeper.Name("router"))
+//	c.Register(new(HelloCtl), keeper.Name("helloCtl"))
+//	c.Start(ctx)
+package web
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/tooky0630/keeper"
+)
+
+// Route is a handler served under a pattern. An empty Method matches any method.
+type Route struct {
+	Method  string
+	Pattern string
+	Handler http.Handler
+}
+
+// RouteProvider is implemented by beans which serve http routes.
+type RouteProvider interface {
+	Routes() []Route
+}
+
+// Mux is the router which routes are mounted onto, *http.ServeMux satisfies it.
+type Mux interface {
+	Handle(pattern string, handler http.Handler)
+}
+
+// methodMux is implemented by routers with per method registration, like chi.Router.
+type methodMux interface {
+	Method(method, pattern string, handler http.Handler)
+}
+
+// Router mounts the routes of every RouteProvider bean onto Mux on Start.
+type Router struct {
+	Mux     Mux
+	keeper  keeper.Keeper
+	methods map[string]methodRoutes // by pattern, for a Mux without methodMux
+}
+
+// NewRouter returns a Router collecting RouteProvider beans of k.
+func NewRouter(k keeper.Keeper, mux Mux) *Router {
+	return &Router{Mux: mux, keeper: k}
+}
+
+// ServeHTTP dispatches the request to the mounted routes.
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	h, ok := r.Mux.(http.Handler)
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	h.ServeHTTP(w, req)
+}
+
+// Start mounts the routes of all RouteProvider beans, in order of bean name.
+func (r *Router) Start(context.Context) error {
+	beans := r.keeper.All()
+	names := make([]string, 0, len(beans))
+	for name, bean := range beans {
+		if _, ok := bean.(RouteProvider); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, route := range beans[name].(RouteProvider).Routes() {
+			if err := r.mount(route); err != nil {
+				return fmt.Errorf("mount routes of %s: %v", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// mount registers a single route, turning the panic of a conflicting pattern into an error.
+func (r *Router) mount(route Route) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("%s %s: %v", route.Method, route.Pattern, p)
+		}
+	}()
+	if route.Method == "" {
+		r.Mux.Handle(route.Pattern, route.Handler)
+		return nil
+	}
+	if mm, ok := r.Mux.(methodMux); ok {
+		mm.Method(route.Method, route.Pattern, route.Handler)
+		return nil
+	}
+	routes, ok := r.methods[route.Pattern]
+	if !ok {
+		if r.methods == nil {
+			r.methods = make(map[string]methodRoutes)
+		}
+		routes = make(methodRoutes)
+		r.Mux.Handle(route.Pattern, routes)
+		r.methods[route.Pattern] = routes
+	}
+	if _, dup := routes[route.Method]; dup {
+		return fmt.Errorf("%s %s: route already mounted", route.Method, route.Pattern)
+	}
+	routes[route.Method] = route.Handler
+	return nil
+}
+
+// methodRoutes serves the routes of a pattern by method, and answers 405
+// for the other methods, as a Mux like http.ServeMux before Go 1.22 has no
+// method patterns. It's complete once the Router has started.
+type methodRoutes map[string]http.Handler
+
+func (m methodRoutes) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if h, ok := m[req.Method]; ok {
+		h.ServeHTTP(w, req)
+		return
+	}
+	allowed := make([]string, 0, len(m))
+	for method := range m {
+		allowed = append(allowed, method)
+	}
+	sort.Strings(allowed)
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+}
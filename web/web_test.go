@@ -0,0 +1,87 @@
+package web
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tooky0630/keeper"
+)
+
+type helloCtl struct{}
+
+func (ctl *helloCtl) Routes() []Route {
+	return []Route{{Pattern: "/hello", Handler: http.HandlerFunc(ctl.hello)}}
+}
+
+func (ctl *helloCtl) hello(w http.ResponseWriter, _ *http.Request) {
+	w.Write([]byte("Hello World"))
+}
+
+func TestRouter_Start(t *testing.T) {
+	c := keeper.New()
+	router := NewRouter(c, http.NewServeMux())
+	if err := c.Register(router, keeper.Name("router")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Register(new(helloCtl), keeper.Name("helloCtl")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/hello", nil))
+	body, _ := ioutil.ReadAll(rec.Body)
+	if string(body) != "Hello World" {
+		t.Fatalf("got %q", body)
+	}
+}
+
+func TestRouter_StartConflict(t *testing.T) {
+	c := keeper.New()
+	if err := c.Register(NewRouter(c, http.NewServeMux()), keeper.Name("router")); err != nil {
+		t.Fatal(err)
+	}
+	c.Register(new(helloCtl), keeper.Name("a"))
+	c.Register(new(helloCtl), keeper.Name("b"))
+	if err := c.Start(context.Background()); err == nil {
+		t.Fatal("expected conflicting patterns to fail")
+	}
+}
+
+type itemsCtl struct{}
+
+func (ctl *itemsCtl) Routes() []Route {
+	reply := func(body string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.Write([]byte(body)) })
+	}
+	return []Route{
+		{Method: http.MethodGet, Pattern: "/items", Handler: reply("list")},
+		{Method: http.MethodPost, Pattern: "/items", Handler: reply("created")},
+	}
+}
+
+func TestRouter_StartMethod(t *testing.T) {
+	c := keeper.New()
+	router := NewRouter(c, http.NewServeMux())
+	c.Register(router, keeper.Name("router"))
+	c.Register(new(itemsCtl), keeper.Name("itemsCtl"))
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for method, want := range map[string]string{http.MethodGet: "list", http.MethodPost: "created"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(method, "/items", nil))
+		if body, _ := ioutil.ReadAll(rec.Body); string(body) != want {
+			t.Errorf("%s /items = %d %q, want %q", method, rec.Code, body, want)
+		}
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/items", nil))
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != "GET, POST" {
+		t.Fatalf("DELETE /items = %d, Allow %q", rec.Code, rec.Header().Get("Allow"))
+	}
+}
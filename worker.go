@@ -0,0 +1,129 @@
+package keeper
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Worker is implemented by beans which run in the background for the lifetime
+// of the container, like consumers and pollers. Run should return when ctx is done.
+type Worker interface {
+	Run(ctx context.Context) error
+}
+
+// RestartPolicy decides how a Worker is restarted after Run returns an error.
+type RestartPolicy struct {
+	// MaxRestarts limits the restarts of a worker, negative means no limit.
+	MaxRestarts int
+	// MinBackoff is the delay before the first restart, the one of
+	// DefaultRestartPolicy when not positive, doubled on each following
+	// restart up to MaxBackoff, which is no limit when not positive.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultRestartPolicy restarts failed workers forever, waiting up to 30 seconds in between.
+var DefaultRestartPolicy = RestartPolicy{
+	MaxRestarts: -1,
+	MinBackoff:  100 * time.Millisecond,
+	MaxBackoff:  30 * time.Second,
+}
+
+// WithRestartPolicy is an Option that replaces DefaultRestartPolicy for the workers of the container.
+func WithRestartPolicy(policy RestartPolicy) Option {
+	return optionFunc(func(c *Container) {
+		c.restartPolicy = policy
+	})
+}
+
+// startWorkers runs every Worker bean in its own goroutine until Close, and
+// every Scheduled bean on its schedule.
+func (c *Container) startWorkers() error {
+	schedules, err := c.scheduledJobs()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c.stopWorkers = cancel
+	for _, nb := range c.ordered() {
+		if job, ok := nb.bean.(Scheduled); ok {
+			c.workers.Add(1)
+			go func(name string, job Scheduled) {
+				defer c.workers.Done()
+				c.runJob(ctx, name, job, schedules[name])
+			}(nb.name, job)
+			continue
+		}
+		w, ok := nb.bean.(Worker)
+		if !ok {
+			continue
+		}
+		c.workers.Add(1)
+		go func(name string, w Worker) {
+			defer c.workers.Done()
+			c.supervise(ctx, name, w)
+		}(nb.name, w)
+	}
+	return nil
+}
+
+// supervise runs w and restarts it on failure according to the restart policy.
+func (c *Container) supervise(ctx context.Context, name string, w Worker) {
+	policy := c.restartPolicy
+	backoff := policy.MinBackoff
+	if backoff <= 0 { // it would never grow, restarting in a hot loop
+		backoff = DefaultRestartPolicy.MinBackoff
+	}
+	for restarts := 0; ; restarts++ {
+		err := runWorker(ctx, w)
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+		if policy.MaxRestarts >= 0 && restarts >= policy.MaxRestarts {
+			c.logger.Printf("keeper: worker %s failed: %v, giving up after %d restarts", name, err, restarts)
+			return
+		}
+		c.logger.Printf("keeper: worker %s failed: %v, restarting in %v", name, err, backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff < math.MaxInt64/2 { // uncapped, it would overflow eventually
+			backoff *= 2
+		}
+		if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
+
+// panicError is the error of a Worker which panicked.
+type panicError struct {
+	value interface{}
+}
+
+func (e panicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.value)
+}
+
+// runWorker runs w, turning a panic into an error.
+func runWorker(ctx context.Context, w Worker) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = panicError{value: p}
+		}
+	}()
+	return w.Run(ctx)
+}
+
+// stopAllWorkers stops the workers started by Start and waits for them to return.
+func (c *Container) stopAllWorkers() {
+	if c.stopWorkers != nil {
+		c.stopWorkers()
+		c.workers.Wait()
+		c.stopWorkers = nil
+	}
+}
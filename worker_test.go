@@ -0,0 +1,122 @@
+package keeper
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type flakyWorker struct {
+	runs int32
+}
+
+func (w *flakyWorker) Run(ctx context.Context) error {
+	if atomic.AddInt32(&w.runs, 1) < 3 {
+		return errors.New("connection reset")
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestContainer_Worker(t *testing.T) {
+	c := New(WithRestartPolicy(RestartPolicy{MaxRestarts: -1, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}))
+	w := new(flakyWorker)
+	if err := c.Register(w, Name("consumer")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&w.runs) < 3 {
+		if time.Now().After(deadline) {
+			t.Fatal("worker is not restarted")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestContainer_WorkerMaxRestarts(t *testing.T) {
+	c := New(WithRestartPolicy(RestartPolicy{MaxRestarts: 1, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}))
+	w := new(flakyWorker)
+	c.Register(w, Name("consumer"))
+	c.Start(context.Background())
+	time.Sleep(50 * time.Millisecond)
+	c.Close()
+	if runs := atomic.LoadInt32(&w.runs); runs != 2 {
+		t.Fatalf("got %d runs, want 2", runs)
+	}
+}
+
+type blockingWorker struct {
+	runs int32
+}
+
+func (w *blockingWorker) Run(ctx context.Context) error {
+	atomic.AddInt32(&w.runs, 1)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestContainer_StartTwice(t *testing.T) {
+	c := New()
+	w := new(blockingWorker)
+	c.Register(w, Name("consumer"))
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Start(context.Background()); !errors.Is(err, ErrStarted) {
+		t.Fatalf("second Start = %v, want ErrStarted", err)
+	}
+	closed := make(chan error)
+	go func() { closed <- c.Close() }()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close hangs after a second Start")
+	}
+	if runs := atomic.LoadInt32(&w.runs); runs != 1 {
+		t.Fatalf("got %d runs, want 1", runs)
+	}
+}
+
+type failingWorker struct {
+	runs int32
+}
+
+func (w *failingWorker) Run(context.Context) error {
+	atomic.AddInt32(&w.runs, 1)
+	return errors.New("connection refused")
+}
+
+func TestContainer_WorkerUncappedBackoff(t *testing.T) {
+	c := New(WithRestartPolicy(RestartPolicy{MaxRestarts: -1, MinBackoff: 10 * time.Millisecond}))
+	w := new(failingWorker)
+	c.Register(w, Name("consumer"))
+	c.Start(context.Background())
+	time.Sleep(100 * time.Millisecond)
+	c.Close()
+	if runs := atomic.LoadInt32(&w.runs); runs > 5 { // 0, 10, 30, 70ms
+		t.Fatalf("got %d runs in 100ms, the backoff should double without MaxBackoff", runs)
+	}
+}
+
+func TestContainer_WorkerZeroBackoff(t *testing.T) {
+	c := New(WithRestartPolicy(RestartPolicy{MaxRestarts: -1}))
+	w := new(failingWorker)
+	c.Register(w, Name("consumer"))
+	c.Start(context.Background())
+	time.Sleep(50 * time.Millisecond)
+	c.Close()
+	if runs := atomic.LoadInt32(&w.runs); runs > 2 {
+		t.Fatalf("got %d runs in 50ms, the backoff should default to DefaultRestartPolicy.MinBackoff", runs)
+	}
+}